@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rates ADD COLUMN method VARCHAR(16) NOT NULL DEFAULT 'top';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rates DROP COLUMN IF EXISTS method;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rates
+    ADD COLUMN mid DECIMAL(20,8),
+    ADD COLUMN spread DECIMAL(20,8),
+    ADD COLUMN spread_bps DECIMAL(20,8);
+
+UPDATE rates
+SET mid = ROUND((ask + bid) / 2, 8),
+    spread = ask - bid,
+    spread_bps = ROUND((ask - bid) * 10000 / ((ask + bid) / 2), 8)
+WHERE ask + bid > 0;
+
+UPDATE rates SET mid = 0, spread = 0, spread_bps = 0 WHERE mid IS NULL;
+
+ALTER TABLE rates
+    ALTER COLUMN mid SET NOT NULL,
+    ALTER COLUMN spread SET NOT NULL,
+    ALTER COLUMN spread_bps SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rates
+    DROP COLUMN IF EXISTS mid,
+    DROP COLUMN IF EXISTS spread,
+    DROP COLUMN IF EXISTS spread_bps;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rates ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'grinex';
+ALTER TABLE rates ALTER COLUMN source DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rates DROP COLUMN IF EXISTS source;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE trades (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(32) NOT NULL,
+    market VARCHAR(20) NOT NULL,
+    trade_id BIGINT NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    volume DECIMAL(20,8) NOT NULL,
+    amount DECIMAL(20,8) NOT NULL,
+    side VARCHAR(8) NOT NULL,
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, market, trade_id)
+);
+
+CREATE INDEX idx_trades_market_timestamp ON trades(market, timestamp);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_trades_market_timestamp;
+DROP TABLE IF EXISTS trades;
+-- +goose StatementEnd
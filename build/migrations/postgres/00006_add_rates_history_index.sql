@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_rates_market_timestamp_id ON rates(market, timestamp, id);
+DROP INDEX IF EXISTS idx_rates_market_timestamp;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX idx_rates_market_timestamp ON rates(market, timestamp);
+DROP INDEX IF EXISTS idx_rates_market_timestamp_id;
+-- +goose StatementEnd
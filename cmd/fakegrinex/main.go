@@ -0,0 +1,92 @@
+// Command fakegrinex serves a fake Grinex REST API with random-walk order books and injected faults,
+// for local runs and integration tests without access to grinex.io.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"usdt-rate-service/internal/fakegrinex"
+	"usdt-rate-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "HTTP listen address")
+	markets := flag.String("markets", "usdtrub=80,btcusdt=100000", "Generated markets with their initial mid price")
+	fixtures := flag.String("fixtures", "", "Directory of <market>.json depth responses served as fixed books")
+	levels := flag.Int("levels", 20, "Levels on each side of generated books")
+	volatility := flag.Float64("volatility", 0.0005, "Relative price change between depth requests")
+	latency := flag.Duration("latency", 0, "Delay of every response")
+	jitter := flag.Duration("jitter", 0, "Maximum random delay added to the latency")
+	errorRate := flag.Float64("error-rate", 0, "Share of requests failing with -error-status, from 0 to 1")
+	errorStatus := flag.Int("error-status", http.StatusServiceUnavailable, "HTTP status of injected failures")
+	malformedRate := flag.Float64("malformed-rate", 0, "Share of requests answered with a body that is not JSON")
+	emptySideRate := flag.Float64("empty-side-rate", 0, "Share of depth responses with a side missing")
+	seed := flag.Uint64("seed", 0, "Seed of the generated data, 0 picks a random one")
+	logLevel := flag.String("log-level", "info", "Log level")
+	flag.Parse()
+
+	logger := logger.MustLoadLogger(*logLevel)
+
+	config := fakegrinex.Config{
+		Markets:       make(map[string]float64),
+		Levels:        *levels,
+		Volatility:    *volatility,
+		Latency:       *latency,
+		Jitter:        *jitter,
+		ErrorRate:     *errorRate,
+		ErrorStatus:   *errorStatus,
+		MalformedRate: *malformedRate,
+		EmptySideRate: *emptySideRate,
+		Seed:          *seed,
+	}
+	for _, pair := range strings.Split(*markets, ",") {
+		market, price, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		mid, err := strconv.ParseFloat(price, 64)
+		if err != nil || mid <= 0 {
+			logger.Fatal("invalid market price", zap.String("market", market), zap.String("price", price))
+		}
+		config.Markets[market] = mid
+	}
+	if *fixtures != "" {
+		books, err := fakegrinex.LoadBooks(*fixtures)
+		if err != nil {
+			logger.Fatal("failed to load fixture books", zap.Error(err))
+		}
+		config.Books = books
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           fakegrinex.New(logger, config),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		logger.Info("Fake Grinex listening", zap.String("address", *addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("failed to serve", zap.Error(err))
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("failed to shut down", zap.Error(err))
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,6 +10,7 @@ import (
 	"usdt-rate-service/internal/adapter"
 	handler "usdt-rate-service/internal/handler/grpc"
 	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/provider"
 	"usdt-rate-service/internal/repository"
 	server "usdt-rate-service/internal/server/grpc"
 	"usdt-rate-service/internal/service"
@@ -36,14 +38,170 @@ func main() {
 
 	ratesRepo := repository.NewRates(pgPool)
 
-	grinex, err := grinex.NewClient(config.GrinexAddress)
+	retryPolicy := grinex.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = config.GrinexRetryMaxAttempts
+	retryPolicy.InitialBackoff = config.GrinexRetryInitialBackoff
+	retryPolicy.MaxBackoff = config.GrinexRetryMaxBackoff
+
+	rateLimitMode := grinex.RateLimitMode(config.GrinexRateLimitMode)
+	if rateLimitMode != grinex.RateLimitWait && rateLimitMode != grinex.RateLimitFailFast {
+		logger.Fatal("unknown grinex rate limit mode", zap.String("mode", config.GrinexRateLimitMode))
+	}
+
+	tlsConfig, err := grinex.LoadTLSConfig(
+		config.GrinexCAFile, config.GrinexClientCertFile, config.GrinexClientKeyFile)
+	if err != nil {
+		logger.Fatal("failed to load grinex TLS settings", zap.Error(err))
+	}
+	grinexOptions := []grinex.Option{
+		grinex.WithLogger(logger),
+		grinex.WithRetryPolicy(retryPolicy),
+		grinex.WithRateLimit(config.GrinexRateLimit, config.GrinexRateLimitBurst, rateLimitMode),
+		grinex.WithTimeout(config.GrinexTimeout),
+		grinex.WithDialTimeout(config.GrinexDialTimeout),
+		grinex.WithTLSHandshakeTimeout(config.GrinexTLSHandshakeTimeout),
+		grinex.WithResponseHeaderTimeout(config.GrinexResponseHeaderTimeout),
+		grinex.WithHeaders(config.GrinexHeaders),
+		grinex.WithTLSConfig(tlsConfig),
+		grinex.WithIdleConns(
+			config.GrinexMaxIdleConns, config.GrinexMaxIdleConnsPerHost, config.GrinexIdleConnTimeout),
+		grinex.WithMaxBodySize(int64(config.GrinexMaxBodySize)),
+	}
+	if config.GrinexProxy != "" {
+		proxyURL, err := url.Parse(config.GrinexProxy)
+		if err != nil {
+			logger.Fatal("invalid grinex proxy URL", zap.Error(err))
+		}
+		grinexOptions = append(grinexOptions, grinex.WithProxy(proxyURL))
+	}
+
+	grinexClient, err := grinex.NewClient(config.GrinexAddress, grinexOptions...)
 	if err != nil {
 		logger.Fatal("failed to create grinex client", zap.Error(err))
 	}
 
-	depthProvider := adapter.NewGrinexDepthProvider(grinex)
+	// Circuit breaker states reported by the health check.
+	circuits := provider.NewCircuitStates()
+	// Depth providers available to the fallback chain, by name.
+	depthProviders := map[string]service.DepthProvider{
+		adapter.GrinexSource: newCircuitBreaker(logger, config, circuits, adapter.GrinexSource,
+			adapter.NewGrinexDepthProvider(grinexClient)),
+	}
+	// Recorded depth data replaces Grinex, so that the service can run offline: the stream and the trades,
+	// which have no recordings, are disabled.
+	offline := config.DepthReplayDir != ""
+	if config.GrinexStreamAddress != "" && offline {
+		logger.Warn("grinex order book stream disabled while replaying recorded depth data")
+	}
+	if config.GrinexStreamAddress != "" && !offline {
+		stream, err := grinex.NewBookStream(grinexClient, config.GrinexStreamAddress, config.GrinexStreamMarkets,
+			grinex.WithStreamLogger(logger),
+			grinex.WithStreamReadTimeout(config.GrinexStreamReadTimeout),
+			grinex.WithReconnectPolicy(retryPolicy),
+		)
+		if err != nil {
+			logger.Fatal("failed to create grinex order book stream", zap.Error(err))
+		}
+		go stream.Run(ctx)
+		depthProviders[adapter.GrinexStreamSource] = adapter.NewGrinexStreamDepthProvider(stream)
+	}
+	// Markets mapped for REST providers are supported even if Grinex does not list them.
+	var restMarkets []string
+	if config.RESTProvidersFile != "" {
+		restConfigs, err := provider.LoadRESTConfigs(config.RESTProvidersFile)
+		if err != nil {
+			logger.Fatal("failed to load REST depth providers", zap.Error(err))
+		}
+		for _, restConfig := range restConfigs {
+			if _, ok := depthProviders[restConfig.Name]; ok {
+				logger.Fatal("duplicate depth provider", zap.String("provider", restConfig.Name))
+			}
+			rest, err := provider.NewREST(restConfig)
+			if err != nil {
+				logger.Fatal("invalid REST depth provider", zap.Error(err))
+			}
+			depthProviders[restConfig.Name] = newCircuitBreaker(logger, config, circuits, restConfig.Name, rest)
+			for market := range restConfig.Markets {
+				restMarkets = append(restMarkets, market)
+			}
+		}
+	}
+	var marketsProvider service.MarketsProvider = adapter.NewGrinexMarketsProvider(grinexClient)
+	if offline {
+		replay, err := provider.NewReplay(logger, config.DepthReplayDir, provider.ReplayMode(config.DepthReplayMode))
+		if err != nil {
+			logger.Fatal("failed to load recorded depth data", zap.Error(err))
+		}
+		depthProviders[provider.ReplaySource] = replay
+		marketsProvider = replay
+	}
+	if len(config.ConsensusProviders) > 0 {
+		aggregation := provider.Aggregation(config.ConsensusAggregation)
+		if aggregation != provider.AggregationMedian && aggregation != provider.AggregationWeighted {
+			logger.Fatal("unknown consensus aggregation", zap.String("aggregation", config.ConsensusAggregation))
+		}
+		if config.ConsensusMinVenues < 1 || config.ConsensusMinVenues > len(config.ConsensusProviders) {
+			logger.Fatal("consensus minimum venues must be between 1 and the number of consensus providers",
+				zap.Int("minVenues", config.ConsensusMinVenues),
+				zap.Int("providers", len(config.ConsensusProviders)),
+			)
+		}
+		if config.ConsensusMaxDeviationBps < 0 {
+			logger.Fatal("negative consensus maximum deviation",
+				zap.Int64("maxDeviationBps", config.ConsensusMaxDeviationBps))
+		}
+		depthProviders[provider.ConsensusSource] = provider.NewConsensus(logger,
+			mustResolveProviders(logger, depthProviders, config.ConsensusProviders),
+			provider.WithAggregation(aggregation),
+			provider.WithMaxDeviationBps(config.ConsensusMaxDeviationBps),
+			provider.WithMinVenues(config.ConsensusMinVenues),
+		)
+	}
+	fallbackOptions := make([]provider.FallbackOption, 0, len(config.MarketDepthProviders))
+	for market, names := range config.MarketDepthProviders {
+		fallbackOptions = append(fallbackOptions,
+			provider.WithMarketOrder(market, mustResolveProviders(logger, depthProviders, names)...))
+	}
+	fallback := provider.NewFallback(logger,
+		mustResolveProviders(logger, depthProviders, config.DepthProviders), fallbackOptions...)
 
-	service := service.NewRatesService(logger, depthProvider, ratesRepo)
+	var recorded service.DepthProvider = fallback
+	if config.DepthRecordDir != "" {
+		recorded, err = provider.NewRecorder(logger, fallback, config.DepthRecordDir)
+		if err != nil {
+			logger.Fatal("failed to create depth recorder", zap.Error(err))
+		}
+	}
+
+	cacheOptions := make([]provider.CacheOption, 0, len(config.MarketDepthCacheTTL)+1)
+	cacheOptions = append(cacheOptions, provider.WithFetchTimeout(config.DepthFetchTimeout))
+	for market, ttl := range config.MarketDepthCacheTTL {
+		cacheOptions = append(cacheOptions, provider.WithMarketTTL(market, ttl))
+	}
+	depthProvider := provider.NewCache(logger, recorded, config.DepthCacheTTL, cacheOptions...)
+
+	serviceOptions := []service.Option{
+		service.WithMaxDataAge(config.MaxDataAge, config.MarketMaxDataAge),
+		service.WithRateAsOfMaxLookback(config.RateAsOfMaxLookback),
+		service.WithCircuitStates(circuits),
+	}
+	if config.MarketsRefreshInterval > 0 {
+		markets := provider.NewMarketRegistry(logger, marketsProvider, config.MarketsRefreshInterval,
+			provider.WithExtraMarkets(restMarkets...))
+		go markets.Run(ctx)
+		serviceOptions = append(serviceOptions, service.WithMarketRegistry(markets))
+	}
+	// Left nil unless enabled, so that the service does not get a typed nil repository.
+	var tradesRepo service.TradesRepository
+	if config.TradesStore {
+		tradesRepo = repository.NewTrades(pgPool)
+	}
+	if !offline {
+		serviceOptions = append(serviceOptions,
+			service.WithTrades(adapter.NewGrinexTradesProvider(grinexClient), tradesRepo))
+	}
+
+	service := service.NewRatesService(logger, depthProvider, ratesRepo, serviceOptions...)
 
 	ratesHandler := handler.NewRatesHandler(service)
 
@@ -66,3 +224,62 @@ func main() {
 	grpcServer.Stop()
 	logger.Info("Shutdown complete")
 }
+
+// newCircuitBreaker wraps the named depth provider in a circuit breaker configured per market
+// that reports its state changes to circuits.
+func newCircuitBreaker(
+	logger *zap.Logger,
+	config *config.Config,
+	circuits *provider.CircuitStates,
+	name string,
+	depthProvider service.DepthProvider,
+) *provider.CircuitBreaker {
+	settings := provider.BreakerSettings{
+		FailureThreshold: config.BreakerFailureThreshold,
+		Cooldown:         config.BreakerCooldown,
+	}
+	markets := make(map[string]provider.BreakerSettings)
+	for market, threshold := range config.MarketBreakerFailureThreshold {
+		marketSettings, ok := markets[market]
+		if !ok {
+			marketSettings = settings
+		}
+		marketSettings.FailureThreshold = threshold
+		markets[market] = marketSettings
+	}
+	for market, cooldown := range config.MarketBreakerCooldown {
+		marketSettings, ok := markets[market]
+		if !ok {
+			marketSettings = settings
+		}
+		marketSettings.Cooldown = cooldown
+		markets[market] = marketSettings
+	}
+
+	opts := make([]provider.BreakerOption, 0, len(markets)+1)
+	opts = append(opts, provider.WithStateChangeHook(circuits.Hook(name)))
+	for market, marketSettings := range markets {
+		opts = append(opts, provider.WithMarketBreakerSettings(market, marketSettings))
+	}
+	return provider.NewCircuitBreaker(logger, name, depthProvider, settings, opts...)
+}
+
+// mustResolveProviders looks up the depth providers by name and exits if one of them is unknown.
+func mustResolveProviders(
+	logger *zap.Logger,
+	providers map[string]service.DepthProvider,
+	names []string,
+) []provider.Named {
+	if len(names) == 0 {
+		logger.Fatal("at least one depth provider is required")
+	}
+	resolved := make([]provider.Named, 0, len(names))
+	for _, name := range names {
+		p, ok := providers[name]
+		if !ok {
+			logger.Fatal("unknown depth provider", zap.String("provider", name))
+		}
+		resolved = append(resolved, provider.Named{Name: name, Provider: p})
+	}
+	return resolved
+}
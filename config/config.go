@@ -2,7 +2,43 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxDataAge               = time.Minute
+	defaultDepthProviders           = "grinex"
+	defaultConsensusAggregation     = "median"
+	defaultConsensusMaxDeviationBps = "100"
+	defaultConsensusMinVenues       = "1"
+	defaultGrinexRetryMaxAttempts   = "3"
+	defaultGrinexRetryInitialDelay  = "200ms"
+	defaultGrinexRetryMaxDelay      = "5s"
+	defaultBreakerFailureThreshold  = "5"
+	defaultBreakerCooldown          = "30s"
+	defaultDepthCacheTTL            = "1s"
+	defaultDepthFetchTimeout        = "30s"
+	defaultGrinexRateLimit          = "5"
+	defaultGrinexRateLimitBurst     = "10"
+	defaultGrinexRateLimitMode      = "wait"
+	defaultGrinexTimeout            = "10s"
+	defaultGrinexDialTimeout        = "5s"
+	defaultGrinexTLSTimeout         = "5s"
+	defaultGrinexHeaderTimeout      = "5s"
+	defaultGrinexMaxIdleConns       = "100"
+	defaultGrinexMaxIdlePerHost     = "10"
+	defaultGrinexIdleConnTimeout    = "90s"
+	defaultGrinexMaxBodySize        = "10485760"
+	defaultMarketsRefreshInterval   = "10m"
+	defaultTradesStore              = "false"
+	defaultGrinexStreamMarkets      = "usdtrub"
+	defaultGrinexStreamReadTimeout  = "30s"
+	defaultDepthReplayMode          = "sequence"
+	defaultRateAsOfMaxLookback      = "24h"
 )
 
 type Config struct {
@@ -10,6 +46,102 @@ type Config struct {
 	DatabaseAddress string
 	GrinexAddress   string
 	LogLevel        string
+	// MaxDataAge is the maximum age of depth data accepted for any market, zero disables the check.
+	MaxDataAge time.Duration
+	// MarketMaxDataAge overrides MaxDataAge for specific markets.
+	MarketMaxDataAge map[string]time.Duration
+	// DepthProviders is the ordered list of depth providers to try for any market.
+	DepthProviders []string
+	// MarketDepthProviders overrides DepthProviders for specific markets.
+	MarketDepthProviders map[string][]string
+	// ConsensusProviders are the venues combined by the "consensus" depth provider,
+	// which is only available when the list is not empty.
+	ConsensusProviders []string
+	// ConsensusAggregation is the way the consensus combines prices: median or weighted.
+	ConsensusAggregation string
+	// ConsensusMaxDeviationBps is the deviation from the median in basis points that excludes a venue.
+	ConsensusMaxDeviationBps int64
+	// ConsensusMinVenues is the minimum number of venues required for a consensus.
+	ConsensusMinVenues int
+	// GrinexRetryMaxAttempts is the total number of attempts of a Grinex request, 1 disables retries.
+	GrinexRetryMaxAttempts int
+	// GrinexRetryInitialBackoff is the delay before the first retry of a Grinex request.
+	GrinexRetryInitialBackoff time.Duration
+	// GrinexRetryMaxBackoff caps the delay between retries of a Grinex request.
+	GrinexRetryMaxBackoff time.Duration
+	// BreakerFailureThreshold is the number of consecutive failures of a depth provider for a market
+	// that opens its circuit breaker, zero disables the breaker.
+	BreakerFailureThreshold int
+	// BreakerCooldown is how long an open circuit breaker rejects requests before probing the provider again.
+	BreakerCooldown time.Duration
+	// MarketBreakerFailureThreshold overrides BreakerFailureThreshold for specific markets.
+	MarketBreakerFailureThreshold map[string]int
+	// MarketBreakerCooldown overrides BreakerCooldown for specific markets.
+	MarketBreakerCooldown map[string]time.Duration
+	// DepthCacheTTL is how long depth data is served from the cache, zero disables caching.
+	DepthCacheTTL time.Duration
+	// MarketDepthCacheTTL overrides DepthCacheTTL for specific markets.
+	MarketDepthCacheTTL map[string]time.Duration
+	// DepthFetchTimeout bounds a depth fetch shared by the requests of a market.
+	DepthFetchTimeout time.Duration
+	// GrinexRateLimit is the maximum number of requests per second sent to Grinex, zero disables the limit.
+	GrinexRateLimit float64
+	// GrinexRateLimitBurst is the number of requests that may be sent to Grinex at once.
+	GrinexRateLimitBurst int
+	// GrinexRateLimitMode is what happens when the limit is reached: "wait" queues the request
+	// until the context deadline, "fail" fails it right away.
+	GrinexRateLimitMode string
+	// GrinexTimeout limits a whole Grinex request attempt, zero means no limit.
+	GrinexTimeout time.Duration
+	// GrinexDialTimeout limits establishing a TCP connection to Grinex.
+	GrinexDialTimeout time.Duration
+	// GrinexTLSHandshakeTimeout limits the TLS handshake with Grinex.
+	GrinexTLSHandshakeTimeout time.Duration
+	// GrinexResponseHeaderTimeout limits waiting for the response headers of Grinex.
+	GrinexResponseHeaderTimeout time.Duration
+	// GrinexProxy is the URL of an HTTP, HTTPS or SOCKS5 proxy for Grinex requests.
+	// If empty, the standard proxy environment variables are used.
+	GrinexProxy string
+	// GrinexHeaders are extra headers sent with every Grinex request.
+	// They may hold credentials, so they are left out when the config is logged.
+	GrinexHeaders map[string]string `json:"-"`
+	// GrinexCAFile is a PEM file with CA certificates trusted in addition to the system ones.
+	GrinexCAFile string
+	// GrinexClientCertFile and GrinexClientKeyFile are the PEM client certificate and key presented to Grinex.
+	GrinexClientCertFile string
+	GrinexClientKeyFile  string
+	// GrinexMaxIdleConns and GrinexMaxIdleConnsPerHost limit the idle connections kept open to Grinex.
+	GrinexMaxIdleConns        int
+	GrinexMaxIdleConnsPerHost int
+	// GrinexIdleConnTimeout is how long an idle connection to Grinex is kept open.
+	GrinexIdleConnTimeout time.Duration
+	// GrinexMaxBodySize is the size in bytes of the largest Grinex response read, zero means no limit.
+	GrinexMaxBodySize int
+	// MarketsRefreshInterval is how often the list of Grinex markets is refreshed,
+	// zero disables market validation and the market list.
+	MarketsRefreshInterval time.Duration
+	// GrinexStreamAddress is the URL of the Grinex WebSocket API. If set, the order books of
+	// GrinexStreamMarkets are streamed and served by the "grinex-ws" depth provider.
+	GrinexStreamAddress string
+	// GrinexStreamMarkets are the markets whose order books are streamed.
+	GrinexStreamMarkets []string
+	// GrinexStreamReadTimeout is how long the stream waits for a message before reconnecting.
+	GrinexStreamReadTimeout time.Duration
+	// DepthRecordDir is the directory where every depth response is recorded, empty disables recording.
+	DepthRecordDir string
+	// DepthReplayDir is a directory of recorded depth responses. If set, the "replay" depth provider serves them
+	// and the market list is taken from them instead of Grinex.
+	DepthReplayDir string
+	// DepthReplayMode is the order of the replayed responses: sequence or timestamp.
+	DepthReplayMode string
+	// RESTProvidersFile is a JSON file declaring depth providers for exchanges with a REST depth API,
+	// see provider.RESTConfig. Empty adds none.
+	RESTProvidersFile string
+	// RateAsOfMaxLookback limits how long before the requested time GetRateAsOf looks for a stored rate,
+	// zero looks through the whole history.
+	RateAsOfMaxLookback time.Duration
+	// TradesStore enables storing the trades returned by GetTrades in the database.
+	TradesStore bool
 }
 
 func MustLoad() *Config {
@@ -20,6 +152,65 @@ func MustLoad() *Config {
 	dbAddr := flag.String("db-addr", "", "Database address")
 	grinexAddr := flag.String("grinex-addr", "", "Grinex address")
 	logLevel := flag.String("log-level", "", "Log level")
+	maxDataAge := flag.String("max-data-age", "", "Maximum age of depth data, e.g. 30s")
+	marketMaxDataAge := flag.String(
+		"market-max-data-age", "", "Per-market maximum age of depth data, e.g. usdtrub=10s,btcusdt=5s")
+	depthProviders := flag.String("depth-providers", "", "Ordered list of depth providers, e.g. grinex,backup")
+	marketDepthProviders := flag.String(
+		"market-depth-providers", "", "Per-market ordered depth providers, e.g. usdtrub=backup|grinex")
+	consensusProviders := flag.String("consensus-providers", "", "Venues combined by the consensus provider")
+	consensusAggregation := flag.String("consensus-aggregation", "", "Consensus aggregation: median or weighted")
+	consensusMaxDeviationBps := flag.String(
+		"consensus-max-deviation-bps", "", "Maximum deviation of a venue from the median in basis points")
+	consensusMinVenues := flag.String("consensus-min-venues", "", "Minimum number of venues for a consensus")
+	grinexRetryMaxAttempts := flag.String("grinex-retry-max-attempts", "", "Total attempts of a Grinex request")
+	grinexRetryInitialBackoff := flag.String(
+		"grinex-retry-initial-backoff", "", "Delay before the first retry of a Grinex request")
+	grinexRetryMaxBackoff := flag.String("grinex-retry-max-backoff", "", "Maximum delay between Grinex retries")
+	breakerFailureThreshold := flag.String(
+		"breaker-failure-threshold", "", "Consecutive provider failures that open the circuit breaker")
+	breakerCooldown := flag.String("breaker-cooldown", "", "How long an open circuit breaker rejects requests")
+	marketBreakerFailureThreshold := flag.String(
+		"market-breaker-failure-threshold", "", "Per-market circuit breaker failure threshold, e.g. usdtrub=3")
+	marketBreakerCooldown := flag.String(
+		"market-breaker-cooldown", "", "Per-market circuit breaker cooldown, e.g. usdtrub=10s")
+	depthCacheTTL := flag.String("depth-cache-ttl", "", "How long depth data is served from the cache, e.g. 1s")
+	marketDepthCacheTTL := flag.String(
+		"market-depth-cache-ttl", "", "Per-market depth cache TTL, e.g. usdtrub=500ms")
+	depthFetchTimeout := flag.String("depth-fetch-timeout", "", "Timeout of a shared depth fetch, e.g. 30s")
+	grinexRateLimit := flag.String("grinex-rate-limit", "", "Maximum Grinex requests per second, 0 disables the limit")
+	grinexRateLimitBurst := flag.String("grinex-rate-limit-burst", "", "Grinex request burst size")
+	grinexRateLimitMode := flag.String("grinex-rate-limit-mode", "", "Grinex rate limit mode: wait or fail")
+	grinexTimeout := flag.String("grinex-timeout", "", "Timeout of a whole Grinex request attempt")
+	grinexDialTimeout := flag.String("grinex-dial-timeout", "", "Timeout of connecting to Grinex")
+	grinexTLSHandshakeTimeout := flag.String("grinex-tls-handshake-timeout", "", "Timeout of the TLS handshake")
+	grinexResponseHeaderTimeout := flag.String(
+		"grinex-response-header-timeout", "", "Timeout of waiting for the Grinex response headers")
+	grinexProxy := flag.String("grinex-proxy", "", "HTTP, HTTPS or SOCKS5 proxy URL for Grinex requests")
+	grinexHeaders := flag.String(
+		"grinex-headers", "", "Extra headers of Grinex requests, e.g. X-Api-Key: abc, User-Agent: rates")
+	grinexCAFile := flag.String("grinex-ca-file", "", "PEM file with extra CA certificates for Grinex")
+	grinexClientCertFile := flag.String("grinex-client-cert-file", "", "PEM client certificate for Grinex")
+	grinexClientKeyFile := flag.String("grinex-client-key-file", "", "PEM client key for Grinex")
+	grinexMaxIdleConns := flag.String("grinex-max-idle-conns", "", "Maximum idle connections to Grinex")
+	grinexMaxIdleConnsPerHost := flag.String(
+		"grinex-max-idle-conns-per-host", "", "Maximum idle connections per Grinex host")
+	grinexIdleConnTimeout := flag.String(
+		"grinex-idle-conn-timeout", "", "How long an idle connection to Grinex is kept open")
+	grinexMaxBodySize := flag.String("grinex-max-body-size", "", "Largest Grinex response read, in bytes")
+	marketsRefreshInterval := flag.String(
+		"markets-refresh-interval", "", "How often the Grinex market list is refreshed, 0 disables it")
+	grinexStreamAddr := flag.String("grinex-ws-addr", "", "Grinex WebSocket API URL, enables the grinex-ws provider")
+	grinexStreamMarkets := flag.String("grinex-ws-markets", "", "Markets whose order books are streamed")
+	grinexStreamReadTimeout := flag.String(
+		"grinex-ws-read-timeout", "", "How long the Grinex stream waits for a message before reconnecting")
+	depthRecordDir := flag.String("depth-record-dir", "", "Directory to record every depth response to")
+	depthReplayDir := flag.String("depth-replay-dir", "", "Directory of recorded depth responses to replay")
+	depthReplayMode := flag.String("depth-replay-mode", "", "Replay order: sequence or timestamp")
+	restProvidersFile := flag.String("rest-providers-file", "", "JSON file declaring REST depth providers")
+	rateAsOfMaxLookback := flag.String(
+		"rate-as-of-max-lookback", "", "How long before the requested time GetRateAsOf looks for a rate")
+	tradesStore := flag.String("trades-store", "", "Store the trades returned by GetTrades: true or false")
 
 	flag.Parse()
 
@@ -30,6 +221,83 @@ func MustLoad() *Config {
 		"DATABASE_ADDRESS")
 	cfg.GrinexAddress = getConfigValue(*grinexAddr, "GRINEX_ADDRESS")
 	cfg.LogLevel = getConfigValue(*logLevel, "LOG_LEVEL")
+	cfg.MaxDataAge = mustParseDuration(
+		getConfigValueOrDefault(*maxDataAge, "MAX_DATA_AGE", defaultMaxDataAge.String()))
+	cfg.MarketMaxDataAge = mustParseMarketDurations(
+		getConfigValueOrDefault(*marketMaxDataAge, "MARKET_MAX_DATA_AGE", ""))
+	cfg.DepthProviders = splitList(
+		getConfigValueOrDefault(*depthProviders, "DEPTH_PROVIDERS", defaultDepthProviders), ",")
+	cfg.MarketDepthProviders = mustParseMarketLists(
+		getConfigValueOrDefault(*marketDepthProviders, "MARKET_DEPTH_PROVIDERS", ""))
+	cfg.ConsensusProviders = splitList(
+		getConfigValueOrDefault(*consensusProviders, "CONSENSUS_PROVIDERS", ""), ",")
+	cfg.ConsensusAggregation = getConfigValueOrDefault(
+		*consensusAggregation, "CONSENSUS_AGGREGATION", defaultConsensusAggregation)
+	cfg.ConsensusMaxDeviationBps = int64(mustParseInt(getConfigValueOrDefault(
+		*consensusMaxDeviationBps, "CONSENSUS_MAX_DEVIATION_BPS", defaultConsensusMaxDeviationBps)))
+	cfg.ConsensusMinVenues = mustParseInt(
+		getConfigValueOrDefault(*consensusMinVenues, "CONSENSUS_MIN_VENUES", defaultConsensusMinVenues))
+	cfg.GrinexRetryMaxAttempts = mustParseInt(getConfigValueOrDefault(
+		*grinexRetryMaxAttempts, "GRINEX_RETRY_MAX_ATTEMPTS", defaultGrinexRetryMaxAttempts))
+	cfg.GrinexRetryInitialBackoff = mustParseDuration(getConfigValueOrDefault(
+		*grinexRetryInitialBackoff, "GRINEX_RETRY_INITIAL_BACKOFF", defaultGrinexRetryInitialDelay))
+	cfg.GrinexRetryMaxBackoff = mustParseDuration(getConfigValueOrDefault(
+		*grinexRetryMaxBackoff, "GRINEX_RETRY_MAX_BACKOFF", defaultGrinexRetryMaxDelay))
+	cfg.BreakerFailureThreshold = mustParseInt(getConfigValueOrDefault(
+		*breakerFailureThreshold, "BREAKER_FAILURE_THRESHOLD", defaultBreakerFailureThreshold))
+	cfg.BreakerCooldown = mustParseDuration(
+		getConfigValueOrDefault(*breakerCooldown, "BREAKER_COOLDOWN", defaultBreakerCooldown))
+	cfg.MarketBreakerFailureThreshold = mustParseMarketInts(getConfigValueOrDefault(
+		*marketBreakerFailureThreshold, "MARKET_BREAKER_FAILURE_THRESHOLD", ""))
+	cfg.MarketBreakerCooldown = mustParseMarketDurations(
+		getConfigValueOrDefault(*marketBreakerCooldown, "MARKET_BREAKER_COOLDOWN", ""))
+	cfg.DepthCacheTTL = mustParseDuration(
+		getConfigValueOrDefault(*depthCacheTTL, "DEPTH_CACHE_TTL", defaultDepthCacheTTL))
+	cfg.MarketDepthCacheTTL = mustParseMarketDurations(
+		getConfigValueOrDefault(*marketDepthCacheTTL, "MARKET_DEPTH_CACHE_TTL", ""))
+	cfg.DepthFetchTimeout = mustParseDuration(
+		getConfigValueOrDefault(*depthFetchTimeout, "DEPTH_FETCH_TIMEOUT", defaultDepthFetchTimeout))
+	cfg.GrinexRateLimit = mustParseFloat(
+		getConfigValueOrDefault(*grinexRateLimit, "GRINEX_RATE_LIMIT", defaultGrinexRateLimit))
+	cfg.GrinexRateLimitBurst = mustParseInt(
+		getConfigValueOrDefault(*grinexRateLimitBurst, "GRINEX_RATE_LIMIT_BURST", defaultGrinexRateLimitBurst))
+	cfg.GrinexRateLimitMode = getConfigValueOrDefault(
+		*grinexRateLimitMode, "GRINEX_RATE_LIMIT_MODE", defaultGrinexRateLimitMode)
+	cfg.GrinexTimeout = mustParseDuration(
+		getConfigValueOrDefault(*grinexTimeout, "GRINEX_TIMEOUT", defaultGrinexTimeout))
+	cfg.GrinexDialTimeout = mustParseDuration(
+		getConfigValueOrDefault(*grinexDialTimeout, "GRINEX_DIAL_TIMEOUT", defaultGrinexDialTimeout))
+	cfg.GrinexTLSHandshakeTimeout = mustParseDuration(getConfigValueOrDefault(
+		*grinexTLSHandshakeTimeout, "GRINEX_TLS_HANDSHAKE_TIMEOUT", defaultGrinexTLSTimeout))
+	cfg.GrinexResponseHeaderTimeout = mustParseDuration(getConfigValueOrDefault(
+		*grinexResponseHeaderTimeout, "GRINEX_RESPONSE_HEADER_TIMEOUT", defaultGrinexHeaderTimeout))
+	cfg.GrinexProxy = getConfigValueOrDefault(*grinexProxy, "GRINEX_PROXY", "")
+	cfg.GrinexHeaders = mustParseHeaders(getConfigValueOrDefault(*grinexHeaders, "GRINEX_HEADERS", ""))
+	cfg.GrinexCAFile = getConfigValueOrDefault(*grinexCAFile, "GRINEX_CA_FILE", "")
+	cfg.GrinexClientCertFile = getConfigValueOrDefault(*grinexClientCertFile, "GRINEX_CLIENT_CERT_FILE", "")
+	cfg.GrinexClientKeyFile = getConfigValueOrDefault(*grinexClientKeyFile, "GRINEX_CLIENT_KEY_FILE", "")
+	cfg.GrinexMaxIdleConns = mustParseInt(
+		getConfigValueOrDefault(*grinexMaxIdleConns, "GRINEX_MAX_IDLE_CONNS", defaultGrinexMaxIdleConns))
+	cfg.GrinexMaxIdleConnsPerHost = mustParseInt(getConfigValueOrDefault(
+		*grinexMaxIdleConnsPerHost, "GRINEX_MAX_IDLE_CONNS_PER_HOST", defaultGrinexMaxIdlePerHost))
+	cfg.GrinexIdleConnTimeout = mustParseDuration(getConfigValueOrDefault(
+		*grinexIdleConnTimeout, "GRINEX_IDLE_CONN_TIMEOUT", defaultGrinexIdleConnTimeout))
+	cfg.GrinexMaxBodySize = mustParseInt(
+		getConfigValueOrDefault(*grinexMaxBodySize, "GRINEX_MAX_BODY_SIZE", defaultGrinexMaxBodySize))
+	cfg.MarketsRefreshInterval = mustParseDuration(getConfigValueOrDefault(
+		*marketsRefreshInterval, "MARKETS_REFRESH_INTERVAL", defaultMarketsRefreshInterval))
+	cfg.GrinexStreamAddress = getConfigValueOrDefault(*grinexStreamAddr, "GRINEX_WS_ADDRESS", "")
+	cfg.GrinexStreamMarkets = splitList(
+		getConfigValueOrDefault(*grinexStreamMarkets, "GRINEX_WS_MARKETS", defaultGrinexStreamMarkets), ",")
+	cfg.GrinexStreamReadTimeout = mustParseDuration(getConfigValueOrDefault(
+		*grinexStreamReadTimeout, "GRINEX_WS_READ_TIMEOUT", defaultGrinexStreamReadTimeout))
+	cfg.DepthRecordDir = getConfigValueOrDefault(*depthRecordDir, "DEPTH_RECORD_DIR", "")
+	cfg.DepthReplayDir = getConfigValueOrDefault(*depthReplayDir, "DEPTH_REPLAY_DIR", "")
+	cfg.DepthReplayMode = getConfigValueOrDefault(*depthReplayMode, "DEPTH_REPLAY_MODE", defaultDepthReplayMode)
+	cfg.RESTProvidersFile = getConfigValueOrDefault(*restProvidersFile, "REST_PROVIDERS_FILE", "")
+	cfg.RateAsOfMaxLookback = mustParseDuration(getConfigValueOrDefault(
+		*rateAsOfMaxLookback, "RATE_AS_OF_MAX_LOOKBACK", defaultRateAsOfMaxLookback))
+	cfg.TradesStore = mustParseBool(getConfigValueOrDefault(*tradesStore, "TRADES_STORE", defaultTradesStore))
 
 	return cfg
 }
@@ -51,3 +319,119 @@ func getConfigValue(flagValue, envKey string) string {
 	// Panic if no value is provided
 	panic("Configuration value not provided for " + envKey)
 }
+
+// getConfigValueOrDefault is like getConfigValue but returns the default value
+// instead of panicking if no value is provided.
+func getConfigValueOrDefault(flagValue, envKey, defaultValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if envValue := os.Getenv(envKey); envValue != "" {
+		return envValue
+	}
+	return defaultValue
+}
+
+// mustParseDuration parses a duration such as "30s" and panics if it is invalid.
+func mustParseDuration(value string) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid duration %q: %v", value, err))
+	}
+	return duration
+}
+
+// mustParseInt parses an integer and panics if it is invalid.
+func mustParseInt(value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid integer %q: %v", value, err))
+	}
+	return i
+}
+
+// mustParseFloat parses a floating point number and panics if it is invalid.
+func mustParseFloat(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid number %q: %v", value, err))
+	}
+	return f
+}
+
+// mustParseBool parses a boolean such as "true" or "0" and panics if it is invalid.
+func mustParseBool(value string) bool {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid boolean %q: %v", value, err))
+	}
+	return b
+}
+
+// mustParseMarketDurations parses a comma-separated list of market=duration pairs,
+// e.g. "usdtrub=10s,btcusdt=5s", and panics if it is invalid.
+func mustParseMarketDurations(value string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for market, duration := range mustParseMarketValues(value) {
+		durations[market] = mustParseDuration(duration)
+	}
+	return durations
+}
+
+// mustParseMarketInts parses a comma-separated list of market=integer pairs,
+// e.g. "usdtrub=3,btcusdt=10", and panics if it is invalid.
+func mustParseMarketInts(value string) map[string]int {
+	ints := make(map[string]int)
+	for market, i := range mustParseMarketValues(value) {
+		ints[market] = mustParseInt(i)
+	}
+	return ints
+}
+
+// mustParseMarketLists parses a comma-separated list of market=a|b|c pairs,
+// e.g. "usdtrub=backup|grinex", and panics if it is invalid.
+func mustParseMarketLists(value string) map[string][]string {
+	lists := make(map[string][]string)
+	for market, list := range mustParseMarketValues(value) {
+		lists[market] = splitList(list, "|")
+	}
+	return lists
+}
+
+// mustParseMarketValues parses a comma-separated list of market=value pairs and panics if it is invalid.
+func mustParseMarketValues(value string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range splitList(value, ",") {
+		market, marketValue, ok := strings.Cut(pair, "=")
+		if !ok {
+			panic(fmt.Sprintf("invalid market value %q, expected market=value", pair))
+		}
+		values[strings.TrimSpace(market)] = strings.TrimSpace(marketValue)
+	}
+	return values
+}
+
+// mustParseHeaders parses a comma-separated list of Name: value pairs,
+// e.g. "X-Api-Key: abc, User-Agent: rates", and panics if it is invalid.
+func mustParseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range splitList(value, ",") {
+		name, headerValue, ok := strings.Cut(pair, ":")
+		if !ok {
+			panic(fmt.Sprintf("invalid header %q, expected Name: value", pair))
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	}
+	return headers
+}
+
+// splitList splits a separated list and drops empty items.
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
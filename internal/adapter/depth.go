@@ -2,10 +2,18 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"usdt-rate-service/internal/infra/grinex"
 	"usdt-rate-service/internal/models"
 )
 
+// GrinexSource is the source name recorded in the depth data returned by GrinexDepthProvider.
+const GrinexSource = "grinex"
+
 // GrinexDepthProvider is an implementation of the DepthProvider interface that uses the Grinex client to get depth data.
 type GrinexDepthProvider struct {
 	client *grinex.Client
@@ -17,41 +25,101 @@ func NewGrinexDepthProvider(client *grinex.Client) *GrinexDepthProvider {
 }
 
 // GetDepth retrieves the depth data for a specific market using the Grinex client.
+// Client errors are wrapped in the domain error describing them, see domainError.
 func (p *GrinexDepthProvider) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
 	dto, err := p.client.GetDepth(ctx, market)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	depth, err := ToDepth(dto)
 	if err != nil {
 		return nil, err
 	}
-	return ToDepth(dto), nil
+	depth.Source = GrinexSource
+	return depth, nil
 }
 
-// ToDepth converts a Grinex DepthResponse DTO to a models.Depth.
-func ToDepth(dto *grinex.DepthResponse) *models.Depth {
-	asks := make([]models.Order, len(dto.Asks))
-	for i, ask := range dto.Asks {
-		asks[i] = models.Order{
-			Price:  ask.Price,
-			Volume: ask.Volume,
-			Amount: ask.Amount,
-			Factor: ask.Factor,
-			Type:   ask.Type,
-		}
+// domainError wraps an error of the Grinex client in the models error describing it:
+// ErrRateLimited, ErrUnknownMarket, ErrUpstreamTimeout, ErrUpstreamUnavailable, also for an order book stream
+// that is not in sync, or, for a response body that is too large or not valid depth JSON, ErrInvalidDepth.
+// Cancellation and unexpected errors are returned as is.
+func domainError(err error) error {
+	var (
+		apiErr       *grinex.APIError
+		netErr       net.Error
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+		fieldErr     *grinex.FieldError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.Is(err, grinex.ErrRateLimited):
+		return fmt.Errorf("%w: %w", models.ErrRateLimited, err)
+	case errors.Is(err, grinex.ErrBookNotReady):
+		return fmt.Errorf("%w: %w", models.ErrUpstreamUnavailable, err)
+	case errors.As(err, &apiErr) && apiErr.UnknownMarket():
+		return fmt.Errorf("%w: %w", models.ErrUnknownMarket, err)
+	case errors.As(err, &apiErr):
+		return fmt.Errorf("%w: %w", models.ErrUpstreamUnavailable, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%w: %w", models.ErrUpstreamTimeout, err)
+	case errors.As(err, &netErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: %w", models.ErrUpstreamUnavailable, err)
+	case errors.As(err, &syntaxErr), errors.As(err, &unmarshalErr), errors.As(err, &fieldErr),
+		errors.Is(err, grinex.ErrResponseTooLarge):
+		return fmt.Errorf("%w: %w", models.ErrInvalidDepth, err)
+	default:
+		return err
 	}
+}
 
-	bids := make([]models.Order, len(dto.Bids))
-	for i, bid := range dto.Bids {
-		bids[i] = models.Order{
-			Price:  bid.Price,
-			Volume: bid.Volume,
-			Amount: bid.Amount,
-			Factor: bid.Factor,
-			Type:   bid.Type,
-		}
+// ToDepth converts a Grinex DepthResponse DTO to a models.Depth.
+// It returns an error wrapping models.ErrInvalidDecimal if a price, volume or amount is not a number.
+func ToDepth(dto *grinex.DepthResponse) (*models.Depth, error) {
+	asks, err := toOrders("asks", dto.Asks)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := toOrders("bids", dto.Bids)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.Depth{
 		Timestamp: dto.Timestamp,
 		Asks:      asks,
 		Bids:      bids,
+	}, nil
+}
+
+// toOrders converts one side of the Grinex order book, parsing the numeric fields.
+// A missing amount is derived from the price and the volume.
+func toOrders(side string, dtos []grinex.Order) ([]models.Order, error) {
+	orders := make([]models.Order, len(dtos))
+	for i, dto := range dtos {
+		price, err := models.ParseDecimal(dto.Price)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d].price: %w", side, i, err)
+		}
+		volume, err := models.ParseDecimal(dto.Volume)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d].volume: %w", side, i, err)
+		}
+		amount := price.Mul(volume)
+		if dto.Amount != "" {
+			if amount, err = models.ParseDecimal(dto.Amount); err != nil {
+				return nil, fmt.Errorf("%s[%d].amount: %w", side, i, err)
+			}
+		}
+
+		orders[i] = models.Order{
+			Price:  price,
+			Volume: volume,
+			Amount: amount,
+			Factor: dto.Factor,
+			Type:   dto.Type,
+		}
 	}
+	return orders, nil
 }
@@ -0,0 +1,105 @@
+package adapter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/adapter"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToDepth(t *testing.T) {
+	t.Run("parses numeric fields", func(t *testing.T) {
+		depth, err := adapter.ToDepth(&grinex.DepthResponse{
+			Timestamp: 1737901234,
+			Asks:      []grinex.Order{{Price: "80.5", Volume: "10", Amount: "805", Type: "limit"}},
+			Bids:      []grinex.Order{{Price: "80.1", Volume: "2", Type: "limit"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "80.5", depth.Asks[0].Price.String())
+		assert.Equal(t, "805", depth.Asks[0].Amount.String())
+		assert.Equal(t, "160.2", depth.Bids[0].Amount.String(), "missing amount is derived")
+	})
+
+	t.Run("non-numeric price", func(t *testing.T) {
+		_, err := adapter.ToDepth(&grinex.DepthResponse{
+			Asks: []grinex.Order{{Price: "80.5", Volume: "10"}},
+			Bids: []grinex.Order{{Price: "80.1", Volume: "1"}, {Price: "abc", Volume: "1"}},
+		})
+		require.ErrorIs(t, err, models.ErrInvalidDecimal)
+		assert.Contains(t, err.Error(), `bids[1].price`)
+	})
+
+	t.Run("non-numeric volume", func(t *testing.T) {
+		_, err := adapter.ToDepth(&grinex.DepthResponse{
+			Asks: []grinex.Order{{Price: "80.5", Volume: ""}},
+		})
+		require.ErrorIs(t, err, models.ErrInvalidDecimal)
+		assert.Contains(t, err.Error(), `asks[0].volume`)
+	})
+}
+
+func TestGrinexDepthProvider_GetDepth_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name: "unknown market",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = io.WriteString(w, `{"errors":["market.does_not_exist"]}`)
+			},
+			wantErr: models.ErrUnknownMarket,
+		},
+		{
+			name: "outage",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+			},
+			wantErr: models.ErrUpstreamUnavailable,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			timeout: 50 * time.Millisecond,
+			wantErr: models.ErrUpstreamTimeout,
+		},
+		{
+			name: "malformed body",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, `{"timestamp": "soon"}`)
+			},
+			wantErr: models.ErrInvalidDepth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			t.Cleanup(server.Close)
+			client, err := grinex.NewClient(server.URL)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			_, err = adapter.NewGrinexDepthProvider(client).GetDepth(ctx, "usdtrub")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
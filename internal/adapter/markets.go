@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"context"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+)
+
+// GrinexMarketsProvider is an implementation of the MarketsProvider interface that uses the Grinex client
+// to list the markets.
+type GrinexMarketsProvider struct {
+	client *grinex.Client
+}
+
+// NewGrinexMarketsProvider creates a new GrinexMarketsProvider with the provided Grinex client.
+func NewGrinexMarketsProvider(client *grinex.Client) *GrinexMarketsProvider {
+	return &GrinexMarketsProvider{client: client}
+}
+
+// ListMarkets retrieves the markets listed by Grinex.
+// Client errors are wrapped in the domain error describing them, see domainError.
+func (p *GrinexMarketsProvider) ListMarkets(ctx context.Context) ([]models.Market, error) {
+	dtos, err := p.client.ListMarkets(ctx)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	markets := make([]models.Market, len(dtos))
+	for i, dto := range dtos {
+		markets[i] = models.Market{
+			ID:    dto.ID,
+			Name:  dto.Name,
+			Base:  dto.BaseUnit,
+			Quote: dto.QuoteUnit,
+		}
+	}
+	return markets, nil
+}
@@ -0,0 +1,40 @@
+package adapter
+
+import (
+	"context"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+)
+
+// GrinexStreamSource is the source name recorded in the depth data returned by GrinexStreamDepthProvider.
+const GrinexStreamSource = "grinex-ws"
+
+// GrinexStreamDepthProvider is an implementation of the DepthProvider interface that serves depth data
+// from the order books maintained by a Grinex WebSocket stream, with no request to the exchange.
+type GrinexStreamDepthProvider struct {
+	stream *grinex.BookStream
+}
+
+// NewGrinexStreamDepthProvider creates a new GrinexStreamDepthProvider with the provided stream.
+// The stream must be running, see grinex.BookStream.Run.
+func NewGrinexStreamDepthProvider(stream *grinex.BookStream) *GrinexStreamDepthProvider {
+	return &GrinexStreamDepthProvider{stream: stream}
+}
+
+// GetDepth returns the current order book of a market. A book that is not in sync with the exchange
+// is reported as models.ErrUpstreamUnavailable, so that a fallback provider can take over.
+func (p *GrinexStreamDepthProvider) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dto, err := p.stream.Depth(market)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	depth, err := ToDepth(dto)
+	if err != nil {
+		return nil, err
+	}
+	depth.Source = GrinexStreamSource
+	return depth, nil
+}
@@ -0,0 +1,82 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+)
+
+// GrinexTradesProvider is an implementation of the TradesProvider interface that uses the Grinex client
+// to get the recent trades.
+type GrinexTradesProvider struct {
+	client *grinex.Client
+}
+
+// NewGrinexTradesProvider creates a new GrinexTradesProvider with the provided Grinex client.
+func NewGrinexTradesProvider(client *grinex.Client) *GrinexTradesProvider {
+	return &GrinexTradesProvider{client: client}
+}
+
+// GetTrades retrieves the recent trades of a specific market using the Grinex client.
+// Client errors are wrapped in the domain error describing them, see domainError.
+func (p *GrinexTradesProvider) GetTrades(ctx context.Context, market string, limit int) ([]models.Trade, error) {
+	dtos, err := p.client.GetTrades(ctx, market, limit)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return ToTrades(market, dtos)
+}
+
+// ToTrades converts Grinex Trade DTOs to models.Trade.
+// It returns an error wrapping models.ErrInvalidDecimal if a price, volume or funds value is not a number
+// and models.ErrInvalidTrade if a creation time cannot be parsed. Missing funds are derived
+// from the price and the volume.
+func ToTrades(market string, dtos []grinex.Trade) ([]models.Trade, error) {
+	trades := make([]models.Trade, len(dtos))
+	for i, dto := range dtos {
+		price, err := models.ParseDecimal(dto.Price)
+		if err != nil {
+			return nil, fmt.Errorf("trades[%d].price: %w", i, err)
+		}
+		volume, err := models.ParseDecimal(dto.Volume)
+		if err != nil {
+			return nil, fmt.Errorf("trades[%d].volume: %w", i, err)
+		}
+		amount := price.Mul(volume)
+		if dto.Funds != "" {
+			if amount, err = models.ParseDecimal(dto.Funds); err != nil {
+				return nil, fmt.Errorf("trades[%d].funds: %w", i, err)
+			}
+		}
+		timestamp, err := parseTradeTime(dto.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("trades[%d].created_at: %w", i, err)
+		}
+
+		trades[i] = models.Trade{
+			ID:        dto.ID,
+			Market:    market,
+			Price:     price,
+			Volume:    volume,
+			Amount:    amount,
+			Side:      dto.TakerType,
+			Timestamp: timestamp,
+			Source:    GrinexSource,
+		}
+	}
+	return trades, nil
+}
+
+// parseTradeTime parses a trade creation time, either RFC 3339 or Unix seconds, into Unix seconds.
+func parseTradeTime(value string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, nil
+	}
+	return 0, fmt.Errorf("%w: invalid time %q", models.ErrInvalidTrade, value)
+}
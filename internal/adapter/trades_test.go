@@ -0,0 +1,41 @@
+package adapter_test
+
+import (
+	"testing"
+	"usdt-rate-service/internal/adapter"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTrades(t *testing.T) {
+	t.Run("parses fields", func(t *testing.T) {
+		trades, err := adapter.ToTrades("usdtrub", []grinex.Trade{
+			{ID: 2, Price: "80.5", Volume: "10", Funds: "805", CreatedAt: "2025-01-26T14:20:34Z", TakerType: "buy"},
+			{ID: 1, Price: "80.1", Volume: "2", CreatedAt: "1737901200", TakerType: "sell"},
+		})
+		require.NoError(t, err)
+		require.Len(t, trades, 2)
+		assert.Equal(t, int64(1737901234), trades[0].Timestamp)
+		assert.Equal(t, "805", trades[0].Amount.String())
+		assert.Equal(t, "buy", trades[0].Side)
+		assert.Equal(t, adapter.GrinexSource, trades[0].Source)
+		assert.Equal(t, "usdtrub", trades[0].Market)
+		assert.Equal(t, int64(1737901200), trades[1].Timestamp, "unix seconds are accepted")
+		assert.Equal(t, "160.2", trades[1].Amount.String(), "missing funds are derived")
+	})
+
+	t.Run("non-numeric price", func(t *testing.T) {
+		_, err := adapter.ToTrades("usdtrub", []grinex.Trade{{Price: "abc", Volume: "1", CreatedAt: "1"}})
+		require.ErrorIs(t, err, models.ErrInvalidDecimal)
+		assert.Contains(t, err.Error(), "trades[0].price")
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		_, err := adapter.ToTrades("usdtrub", []grinex.Trade{{Price: "1", Volume: "1", CreatedAt: "yesterday"}})
+		require.ErrorIs(t, err, models.ErrInvalidTrade)
+		assert.Contains(t, err.Error(), "trades[0].created_at")
+	})
+}
@@ -0,0 +1,372 @@
+// Package fakegrinex implements a fake Grinex REST API serving generated or fixture order books,
+// for local runs and for tests of the client error paths.
+package fakegrinex
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/infra/grinex"
+
+	"go.uber.org/zap"
+)
+
+const (
+	depthEndpoint   = "/api/v2/depth"
+	marketsEndpoint = "/api/v2/markets"
+	tradesEndpoint  = "/api/v2/trades"
+
+	defaultLevels      = 20
+	defaultTradesLimit = 50
+	maxTradesLimit     = 1000
+
+	// spread and tick are the spread and the distance between levels of generated books, relative to the mid price.
+	spread = 0.001
+	tick   = 0.0005
+
+	// malformedBody is served with a 200 status to simulate a proxy answering in place of the API.
+	malformedBody = "<html><body>502 Bad Gateway</body></html>"
+)
+
+// quoteUnits are the quote currencies recognized in market identifiers, longest first.
+var quoteUnits = []string{"usdt", "usdc", "rub", "usd", "eur", "btc", "eth"}
+
+// Config configures the books and the faults of a Server.
+type Config struct {
+	// Markets are the markets with generated books, with their initial mid price.
+	Markets map[string]float64
+	// Books are fixed books served for their markets, see LoadBooks. Their prices follow the random walk too.
+	Books map[string]grinex.DepthResponse
+	// Levels is the number of levels on each side of generated books.
+	Levels int
+	// Volatility is the standard deviation of the relative price change between two depth requests.
+	Volatility float64
+	// Latency delays every response, Jitter adds a random delay of up to its value.
+	Latency time.Duration
+	Jitter  time.Duration
+	// ErrorRate is the share of requests, from 0 to 1, answered with ErrorStatus.
+	ErrorRate   float64
+	ErrorStatus int
+	// MalformedRate is the share of requests answered with a body that is not JSON.
+	MalformedRate float64
+	// EmptySideRate is the share of depth responses with the asks or the bids missing.
+	EmptySideRate float64
+	// Seed makes the generated data and faults reproducible, zero picks a random seed.
+	Seed uint64
+}
+
+// Server is an http.Handler serving the depth, markets and trades endpoints of the Grinex API.
+type Server struct {
+	logger *zap.Logger
+	config Config
+	mux    *http.ServeMux
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	markets map[string]*market
+}
+
+// market is the state of a served market: the base book, if any, and the random walk of its prices.
+type market struct {
+	mid     float64
+	factor  float64
+	book    *grinex.DepthResponse
+	tradeID int64
+}
+
+// New creates a new Server with the config.
+func New(logger *zap.Logger, config Config) *Server {
+	if config.Levels <= 0 {
+		config.Levels = defaultLevels
+	}
+	if config.ErrorStatus == 0 {
+		config.ErrorStatus = http.StatusServiceUnavailable
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64() //nolint:gosec // Fake data does not need a secure source.
+	}
+
+	s := &Server{
+		logger:  logger,
+		config:  config,
+		mux:     http.NewServeMux(),
+		rand:    rand.New(rand.NewPCG(seed, seed)), //nolint:gosec // Fake data does not need a secure source.
+		markets: make(map[string]*market, len(config.Markets)+len(config.Books)),
+	}
+	for id, mid := range config.Markets {
+		s.markets[id] = &market{mid: mid, factor: 1}
+	}
+	for id, book := range config.Books {
+		s.markets[id] = &market{mid: bookMid(book), factor: 1, book: &book}
+	}
+
+	s.mux.HandleFunc(depthEndpoint, s.handleDepth)
+	s.mux.HandleFunc(marketsEndpoint, s.handleMarkets)
+	s.mux.HandleFunc(tradesEndpoint, s.handleTrades)
+	return s
+}
+
+// ServeHTTP applies the configured latency and faults and serves the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.logger.Debug("Request", zap.String("method", r.Method), zap.String("url", r.URL.String()))
+
+	if delay := s.config.Latency + s.randomDuration(s.config.Jitter); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case s.chance(s.config.ErrorRate):
+		writeError(w, s.config.ErrorStatus, "server.unavailable", "injected failure")
+	case s.chance(s.config.MalformedRate):
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(malformedBody))
+	default:
+		s.mux.ServeHTTP(w, r)
+	}
+}
+
+func (s *Server) handleDepth(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("market")
+	s.mu.Lock()
+	m, ok := s.markets[id]
+	if !ok {
+		s.mu.Unlock()
+		writeUnknownMarket(w, id)
+		return
+	}
+	m.factor *= math.Exp(s.rand.NormFloat64() * s.config.Volatility)
+	var depth grinex.DepthResponse
+	if m.book != nil {
+		depth = scaleBook(*m.book, m.factor)
+	} else {
+		depth = s.generateBook(m.mid * m.factor)
+	}
+	if s.chanceLocked(s.config.EmptySideRate) {
+		if s.rand.IntN(2) == 0 {
+			depth.Asks = []grinex.Order{}
+		} else {
+			depth.Bids = []grinex.Order{}
+		}
+	}
+	s.mu.Unlock()
+
+	depth.Timestamp = time.Now().Unix()
+	writeJSON(w, depth)
+}
+
+func (s *Server) handleMarkets(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.markets))
+	for id := range s.markets {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+	slices.Sort(ids)
+
+	markets := make([]grinex.Market, len(ids))
+	for i, id := range ids {
+		base, quote := splitMarket(id)
+		markets[i] = grinex.Market{
+			ID:        id,
+			Name:      strings.ToUpper(base + "/" + quote),
+			BaseUnit:  base,
+			QuoteUnit: quote,
+		}
+	}
+	writeJSON(w, markets)
+}
+
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("market")
+	limit := defaultTradesLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "public.trade.invalid_limit", "invalid limit")
+			return
+		}
+		limit = min(parsed, maxTradesLimit)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.markets[id]
+	if !ok {
+		writeUnknownMarket(w, id)
+		return
+	}
+
+	// Trades happened since the last request, newest first. The identifier advances by at least the limit,
+	// so that every served trade has its own positive identifier and no trade is served twice
+	// with different prices.
+	m.tradeID += int64(limit + s.rand.IntN(limit))
+	mid := m.mid * m.factor
+	now := time.Now()
+	trades := make([]grinex.Trade, limit)
+	for i := range trades {
+		side := "buy"
+		price := mid * (1 + spread/2)
+		if s.rand.IntN(2) == 0 {
+			side = "sell"
+			price = mid * (1 - spread/2)
+		}
+		volume := s.volume()
+		trades[i] = grinex.Trade{
+			ID:        m.tradeID - int64(i),
+			Price:     formatFloat(price),
+			Volume:    formatFloat(volume),
+			Funds:     formatFloat(price * volume),
+			Market:    id,
+			CreatedAt: now.Add(-time.Duration(i) * time.Second).UTC().Format(time.RFC3339),
+			TakerType: side,
+		}
+	}
+	writeJSON(w, trades)
+}
+
+// generateBook generates a book around the mid price. It must be called with s.mu held.
+func (s *Server) generateBook(mid float64) grinex.DepthResponse {
+	depth := grinex.DepthResponse{
+		Asks: make([]grinex.Order, s.config.Levels),
+		Bids: make([]grinex.Order, s.config.Levels),
+	}
+	for i := range s.config.Levels {
+		offset := spread/2 + float64(i)*tick
+		depth.Asks[i] = order(mid*(1+offset), s.volume())
+		depth.Bids[i] = order(mid*(1-offset), s.volume())
+	}
+	return depth
+}
+
+// volume returns a random order volume. It must be called with s.mu held.
+func (s *Server) volume() float64 {
+	return math.Round((1+s.rand.Float64()*999)*100) / 100
+}
+
+// chance reports whether an event with the given probability happens.
+func (s *Server) chance(probability float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chanceLocked(probability)
+}
+
+func (s *Server) chanceLocked(probability float64) bool {
+	return probability > 0 && s.rand.Float64() < probability
+}
+
+// randomDuration returns a random duration from 0 to limit.
+func (s *Server) randomDuration(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.rand.Int64N(int64(limit)))
+}
+
+// LoadBooks loads fixture books from the files named after their market in dir, e.g. "usdtrub.json",
+// each holding a depth response of the Grinex API.
+func LoadBooks(dir string) (map[string]grinex.DepthResponse, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	books := make(map[string]grinex.DepthResponse, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var book grinex.DepthResponse
+		if err = json.Unmarshal(data, &book); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		books[strings.TrimSuffix(filepath.Base(path), ".json")] = book
+	}
+	return books, nil
+}
+
+// scaleBook returns a copy of the book with the prices multiplied by factor.
+func scaleBook(book grinex.DepthResponse, factor float64) grinex.DepthResponse {
+	scale := func(orders []grinex.Order) []grinex.Order {
+		scaled := make([]grinex.Order, len(orders))
+		for i, o := range orders {
+			price, _ := strconv.ParseFloat(o.Price, 64)
+			volume, _ := strconv.ParseFloat(o.Volume, 64)
+			scaled[i] = order(price*factor, volume)
+			scaled[i].Factor, scaled[i].Type = o.Factor, o.Type
+		}
+		return scaled
+	}
+	return grinex.DepthResponse{Asks: scale(book.Asks), Bids: scale(book.Bids)}
+}
+
+// bookMid returns the mid price of the best levels of a book, or 1 if a side is empty.
+func bookMid(book grinex.DepthResponse) float64 {
+	if len(book.Asks) == 0 || len(book.Bids) == 0 {
+		return 1
+	}
+	ask, _ := strconv.ParseFloat(book.Asks[0].Price, 64)
+	bid, _ := strconv.ParseFloat(book.Bids[0].Price, 64)
+	return (ask + bid) / 2
+}
+
+// splitMarket splits a market identifier such as "usdtrub" into its base and quote currencies.
+func splitMarket(id string) (string, string) {
+	for _, quote := range quoteUnits {
+		if base, ok := strings.CutSuffix(id, quote); ok && base != "" {
+			return base, quote
+		}
+	}
+	return id, ""
+}
+
+func order(price, volume float64) grinex.Order {
+	return grinex.Order{
+		Price:  formatFloat(price),
+		Volume: formatFloat(volume),
+		Amount: formatFloat(price * volume),
+		Type:   "limit",
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeUnknownMarket(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, "market.not_found", fmt.Sprintf("market %q does not exist", id))
+}
+
+// writeError writes an error in the format of the Grinex API.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
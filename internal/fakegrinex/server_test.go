@@ -0,0 +1,151 @@
+package fakegrinex_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/adapter"
+	"usdt-rate-service/internal/fakegrinex"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newClient starts a fake Grinex with the config and returns a client of it.
+func newClient(t *testing.T, config fakegrinex.Config, opts ...grinex.Option) *grinex.Client {
+	t.Helper()
+	if config.Markets == nil {
+		config.Markets = map[string]float64{"usdtrub": 80}
+	}
+	config.Seed = 1
+	server := httptest.NewServer(fakegrinex.New(zap.NewNop(), config))
+	t.Cleanup(server.Close)
+
+	client, err := grinex.NewClient(server.URL, opts...)
+	require.NoError(t, err)
+	return client
+}
+
+func TestServer_Depth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid random-walk books", func(t *testing.T) {
+		client := newClient(t, fakegrinex.Config{Levels: 5, Volatility: 0.01})
+
+		first, err := client.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		depth, err := adapter.ToDepth(first)
+		require.NoError(t, err)
+		require.NoError(t, depth.Validate())
+		assert.Len(t, depth.Asks, 5)
+
+		second, err := client.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Asks[0].Price, second.Asks[0].Price)
+	})
+
+	t.Run("fixture books", func(t *testing.T) {
+		dir := t.TempDir()
+		book := `{"asks":[{"price":"81","volume":"2"}],"bids":[{"price":"79","volume":"3"}]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "usdtrub.json"), []byte(book), 0o600))
+		books, err := fakegrinex.LoadBooks(dir)
+		require.NoError(t, err)
+		client := newClient(t, fakegrinex.Config{Markets: map[string]float64{}, Books: books})
+
+		resp, err := client.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, "81.0000", resp.Asks[0].Price)
+		assert.Equal(t, "3.0000", resp.Bids[0].Volume)
+	})
+
+	t.Run("empty sides", func(t *testing.T) {
+		client := newClient(t, fakegrinex.Config{EmptySideRate: 1})
+
+		resp, err := client.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		depth, err := adapter.ToDepth(resp)
+		require.NoError(t, err)
+		require.ErrorIs(t, depth.Validate(), models.ErrInvalidDepth)
+	})
+}
+
+func TestServer_ClientErrorPaths(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown market", func(t *testing.T) {
+		client := newClient(t, fakegrinex.Config{})
+
+		_, err := client.GetDepth(ctx, "usdtrbu")
+		var apiErr *grinex.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.True(t, apiErr.UnknownMarket())
+	})
+
+	t.Run("injected failures are retried", func(t *testing.T) {
+		policy := grinex.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+		client := newClient(t, fakegrinex.Config{ErrorRate: 1}, grinex.WithRetryPolicy(policy))
+
+		_, err := client.GetDepth(ctx, "usdtrub")
+		var apiErr *grinex.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Equal(t, "server.unavailable", apiErr.Code)
+		assert.Equal(t, time.Second, apiErr.RetryAfter)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		client := newClient(t, fakegrinex.Config{MalformedRate: 1})
+
+		_, err := client.GetDepth(ctx, "usdtrub")
+		var syntaxErr *json.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+
+	t.Run("latency beyond the timeout", func(t *testing.T) {
+		client := newClient(t, fakegrinex.Config{Latency: 200 * time.Millisecond},
+			grinex.WithTimeout(20*time.Millisecond))
+
+		_, err := client.GetDepth(ctx, "usdtrub")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err))
+	})
+}
+
+func TestServer_MarketsAndTrades(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, fakegrinex.Config{Markets: map[string]float64{"usdtrub": 80, "btcusdt": 100000}})
+
+	markets, err := client.ListMarkets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []grinex.Market{
+		{ID: "btcusdt", Name: "BTC/USDT", BaseUnit: "btc", QuoteUnit: "usdt"},
+		{ID: "usdtrub", Name: "USDT/RUB", BaseUnit: "usdt", QuoteUnit: "rub"},
+	}, markets)
+
+	seen := make(map[int64]bool)
+	for _, limit := range []int{3, 1, 5} {
+		dtos, err := client.GetTrades(ctx, "usdtrub", limit)
+		require.NoError(t, err)
+		trades, err := adapter.ToTrades("usdtrub", dtos)
+		require.NoError(t, err)
+		require.Len(t, trades, limit)
+		for i, trade := range trades {
+			require.NoError(t, trade.Validate())
+			assert.Positive(t, trade.ID)
+			assert.False(t, seen[trade.ID], "trade %d served twice", trade.ID)
+			seen[trade.ID] = true
+			if i > 0 {
+				assert.Less(t, trade.ID, trades[i-1].ID, "newest first")
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"usdt-rate-service/internal/models"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details attached to the errors of the service.
+const ErrorDomain = "usdt-rate-service"
+
+// Reasons reported in the ErrorInfo details so that callers can tell the failures apart.
+const (
+	ReasonInvalidParams        = "INVALID_PARAMS"
+	ReasonInvalidHistoryQuery  = "INVALID_HISTORY_QUERY"
+	ReasonDepthLevelOutOfRange = "DEPTH_LEVEL_OUT_OF_RANGE"
+	ReasonUnsupportedMarket    = "UNSUPPORTED_MARKET"
+	ReasonUnknownMarket        = "UNKNOWN_MARKET"
+	ReasonUpstreamTimeout      = "UPSTREAM_TIMEOUT"
+	ReasonUpstreamUnavailable  = "UPSTREAM_UNAVAILABLE"
+	ReasonCircuitOpen          = "CIRCUIT_OPEN"
+	ReasonRateLimited          = "RATE_LIMITED"
+	ReasonNoConsensus          = "NO_CONSENSUS"
+	ReasonStaleDepth           = "STALE_DEPTH"
+	ReasonAllProvidersFailed   = "ALL_PROVIDERS_FAILED"
+	ReasonInvalidDepth         = "INVALID_DEPTH"
+	ReasonNoTrades             = "NO_TRADES"
+	ReasonRateNotFound         = "RATE_NOT_FOUND"
+	ReasonInvalidTrade         = "INVALID_TRADE"
+	ReasonNotConfigured        = "NOT_CONFIGURED"
+	ReasonCanceled             = "CANCELED"
+	ReasonInternal             = "INTERNAL"
+)
+
+// statusError converts an error of the service into a gRPC status error with an ErrorInfo detail
+// carrying the reason and the metadata. Internal errors are reported with internalMessage only,
+// so that their details do not leak to callers.
+func statusError(err error, internalMessage string, metadata map[string]string) error {
+	code, reason := classify(err)
+	message := err.Error()
+	if code == codes.Internal {
+		message = internalMessage
+	}
+
+	st := status.New(code, message)
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// classify returns the gRPC code and the reason of an error of the service.
+// The errors of several depth providers are joined under ErrAllProvidersFailed, so it is checked
+// before the errors of a single provider, lest the failure of one provider stand for all of them.
+// The fallback chain returns the error of a lone provider unwrapped, so that its specific cause is reported.
+func classify(err error) (codes.Code, string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidCalculationParams):
+		return codes.InvalidArgument, ReasonInvalidParams
+	case errors.Is(err, models.ErrInvalidHistoryQuery):
+		return codes.InvalidArgument, ReasonInvalidHistoryQuery
+	case errors.Is(err, models.ErrUnsupportedMarket):
+		return codes.InvalidArgument, ReasonUnsupportedMarket
+	case errors.Is(err, models.ErrDepthLevelOutOfRange):
+		return codes.OutOfRange, ReasonDepthLevelOutOfRange
+	case errors.Is(err, models.ErrNotConfigured):
+		return codes.Unimplemented, ReasonNotConfigured
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled, ReasonCanceled
+	case errors.Is(err, models.ErrAllProvidersFailed):
+		return codes.Unavailable, ReasonAllProvidersFailed
+	case errors.Is(err, models.ErrUnknownMarket):
+		return codes.NotFound, ReasonUnknownMarket
+	case errors.Is(err, models.ErrUpstreamTimeout), errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded, ReasonUpstreamTimeout
+	case errors.Is(err, models.ErrCircuitOpen):
+		return codes.Unavailable, ReasonCircuitOpen
+	case errors.Is(err, models.ErrRateLimited):
+		return codes.Unavailable, ReasonRateLimited
+	case errors.Is(err, models.ErrNoConsensus):
+		return codes.Unavailable, ReasonNoConsensus
+	case errors.Is(err, models.ErrStaleDepth):
+		return codes.Unavailable, ReasonStaleDepth
+	case errors.Is(err, models.ErrUpstreamUnavailable):
+		return codes.Unavailable, ReasonUpstreamUnavailable
+	case errors.Is(err, models.ErrInvalidDepth), errors.Is(err, models.ErrInvalidDecimal):
+		// The exchange returned data we cannot use, the message tells exactly what was wrong with it.
+		return codes.FailedPrecondition, ReasonInvalidDepth
+	case errors.Is(err, models.ErrInvalidTrade):
+		return codes.FailedPrecondition, ReasonInvalidTrade
+	case errors.Is(err, models.ErrNoTrades):
+		return codes.NotFound, ReasonNoTrades
+	case errors.Is(err, models.ErrRateNotFound):
+		return codes.NotFound, ReasonRateNotFound
+	default:
+		return codes.Internal, ReasonInternal
+	}
+}
@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"time"
+	"usdt-rate-service/internal/models"
 	"usdt-rate-service/internal/pb"
 	"usdt-rate-service/internal/service"
 
@@ -29,21 +31,275 @@ func (h *RatesHandler) GetRates(ctx context.Context, req *pb.GetRatesRequest) (*
 	if market == "" {
 		return nil, status.Error(codes.InvalidArgument, "market must be specified")
 	}
-	rate, err := h.ratesService.GetRates(ctx, market)
+	method, ok := methodFromProto(req.GetMethod())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown calculation method")
+	}
+	params := service.GetRatesParams{
+		Market: market,
+		Method: method,
+		N:      int(req.GetN()),
+		M:      int(req.GetM()),
+	}
+	if targetVolume := req.GetTargetVolume(); targetVolume != "" {
+		volume, err := models.ParseDecimal(targetVolume)
+		if err != nil || volume.Sign() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "target volume must be a non-negative number")
+		}
+		params.TargetVolume = volume
+	}
+
+	rate, err := h.ratesService.GetRates(ctx, params)
+	if err != nil {
+		return nil, statusError(err, "failed to get rates", map[string]string{"market": market})
+	}
+
+	return &pb.GetRatesResponse{Rate: rateToProto(rate)}, nil
+}
+
+// ListMarkets handles the gRPC request to list the markets rates can be requested for.
+func (h *RatesHandler) ListMarkets(ctx context.Context, _ *pb.ListMarketsRequest) (*pb.ListMarketsResponse, error) {
+	markets, err := h.ratesService.ListMarkets(ctx)
+	if err != nil {
+		return nil, statusError(err, "failed to list markets", nil)
+	}
+
+	resp := &pb.ListMarketsResponse{Markets: make([]*pb.Market, len(markets))}
+	for i, market := range markets {
+		resp.Markets[i] = &pb.Market{
+			Id:    market.ID,
+			Name:  market.Name,
+			Base:  market.Base,
+			Quote: market.Quote,
+		}
+	}
+	return resp, nil
+}
+
+// GetTrades handles the gRPC request to get the most recent trades and the last trade price of a market.
+func (h *RatesHandler) GetTrades(ctx context.Context, req *pb.GetTradesRequest) (*pb.GetTradesResponse, error) {
+	market := req.GetMarket()
+	if market == "" {
+		return nil, status.Error(codes.InvalidArgument, "market must be specified")
+	}
+	if req.GetLimit() > service.MaxTradesLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not exceed %d", service.MaxTradesLimit)
+	}
+
+	recent, err := h.ratesService.GetTrades(ctx, market, int(req.GetLimit()))
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to get rates")
+		return nil, statusError(err, "failed to get trades", map[string]string{"market": market})
 	}
 
-	return &pb.GetRatesResponse{
-		Rate: &pb.Rate{
-			AskPrice:  rate.AskPrice,
-			BidPrice:  rate.BidPrice,
-			Timestamp: rate.Timestamp,
-		},
+	resp := &pb.GetTradesResponse{
+		Trades:        make([]*pb.Trade, len(recent.Trades)),
+		LastPrice:     recent.LastPrice.String(),
+		LastTimestamp: recent.LastTimestamp,
+	}
+	for i, trade := range recent.Trades {
+		resp.Trades[i] = &pb.Trade{
+			Id:        trade.ID,
+			Price:     trade.Price.String(),
+			Volume:    trade.Volume.String(),
+			Amount:    trade.Amount.String(),
+			Side:      trade.Side,
+			Timestamp: trade.Timestamp,
+		}
+	}
+	return resp, nil
+}
+
+// GetRateHistory handles the gRPC request to read back the rates stored for a market, one page at a time.
+func (h *RatesHandler) GetRateHistory(
+	ctx context.Context,
+	req *pb.GetRateHistoryRequest,
+) (*pb.GetRateHistoryResponse, error) {
+	market := req.GetMarket()
+	if market == "" {
+		return nil, status.Error(codes.InvalidArgument, "market must be specified")
+	}
+	if req.GetLimit() > service.MaxRateHistoryLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not exceed %d", service.MaxRateHistoryLimit)
+	}
+
+	history, err := h.ratesService.GetRateHistory(ctx, service.GetRateHistoryParams{
+		Market: market,
+		From:   req.GetFrom(),
+		To:     req.GetTo(),
+		Limit:  int(req.GetLimit()),
+		Cursor: req.GetCursor(),
+	})
+	if err != nil {
+		return nil, statusError(err, "failed to get rate history", map[string]string{"market": market})
+	}
+
+	resp := &pb.GetRateHistoryResponse{
+		Rates:      make([]*pb.Rate, len(history.Rates)),
+		NextCursor: history.NextCursor,
+	}
+	for i := range history.Rates {
+		resp.Rates[i] = rateToProto(&history.Rates[i].Rate)
+	}
+	return resp, nil
+}
+
+// GetCandles handles the gRPC request to get the candles of the rates stored for a market.
+func (h *RatesHandler) GetCandles(ctx context.Context, req *pb.GetCandlesRequest) (*pb.GetCandlesResponse, error) {
+	market := req.GetMarket()
+	if market == "" {
+		return nil, status.Error(codes.InvalidArgument, "market must be specified")
+	}
+	method, ok := methodFromProto(req.GetMethod())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown calculation method")
+	}
+
+	candles, err := h.ratesService.GetCandles(ctx, service.GetCandlesParams{
+		Market:   market,
+		Method:   method,
+		Interval: req.GetInterval(),
+		From:     req.GetFrom(),
+		To:       req.GetTo(),
+		FillGaps: req.GetFillGaps(),
+	})
+	if err != nil {
+		return nil, statusError(err, "failed to get candles", map[string]string{"market": market})
+	}
+
+	resp := &pb.GetCandlesResponse{Candles: make([]*pb.Candle, len(candles))}
+	for i, candle := range candles {
+		resp.Candles[i] = &pb.Candle{
+			Timestamp: candle.Timestamp,
+			Ask:       ohlcToProto(candle.Ask),
+			Bid:       ohlcToProto(candle.Bid),
+			Mid:       ohlcToProto(candle.Mid),
+			Count:     candle.Count,
+		}
+	}
+	return resp, nil
+}
+
+// GetRateAsOf handles the gRPC request to get the rate stored for a market at a point in time.
+func (h *RatesHandler) GetRateAsOf(ctx context.Context, req *pb.GetRateAsOfRequest) (*pb.GetRateAsOfResponse, error) {
+	market := req.GetMarket()
+	if market == "" {
+		return nil, status.Error(codes.InvalidArgument, "market must be specified")
+	}
+	method, ok := methodFromProto(req.GetMethod())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown calculation method")
+	}
+
+	rate, err := h.ratesService.GetRateAsOf(ctx, market, method, req.GetTimestamp())
+	if err != nil {
+		return nil, statusError(err, "failed to get rate", map[string]string{"market": market})
+	}
+	return &pb.GetRateAsOfResponse{Rate: rateToProto(&rate.Rate), Id: rate.ID}, nil
+}
+
+// GetLatestRate handles the gRPC request to get the newest stored rate of a market,
+// fetching it live if it is older than the requested maximum age.
+func (h *RatesHandler) GetLatestRate(
+	ctx context.Context,
+	req *pb.GetLatestRateRequest,
+) (*pb.GetLatestRateResponse, error) {
+	market := req.GetMarket()
+	if market == "" {
+		return nil, status.Error(codes.InvalidArgument, "market must be specified")
+	}
+	if req.GetMaxAgeMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max age must not be negative")
+	}
+
+	latest, err := h.ratesService.GetLatestRate(ctx, market, time.Duration(req.GetMaxAgeMs())*time.Millisecond)
+	if err != nil {
+		return nil, statusError(err, "failed to get latest rate", map[string]string{"market": market})
+	}
+	return &pb.GetLatestRateResponse{
+		Rate:   rateToProto(&latest.Rate),
+		Stored: latest.Stored,
+		AgeMs:  latest.Age.Milliseconds(),
 	}, nil
 }
 
+// HealthCheck reports the service as ok, or as degraded while a circuit breaker is open or half-open,
+// and lists the circuit breaker states.
 func (h *RatesHandler) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
-	// This is a simple health check that always returns OK.
-	return &pb.HealthCheckResponse{Status: "ok"}, nil
+	resp := &pb.HealthCheckResponse{Status: "ok"}
+	for _, circuit := range h.ratesService.CircuitStates() {
+		if circuit.State != models.CircuitClosed {
+			resp.Status = "degraded"
+		}
+		resp.Circuits = append(resp.Circuits, &pb.CircuitState{
+			Provider: circuit.Provider,
+			Market:   circuit.Market,
+			State:    circuit.State,
+			Since:    circuit.Since.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// rateToProto converts a rate to its protobuf message.
+func rateToProto(rate *models.Rate) *pb.Rate {
+	return &pb.Rate{
+		AskPrice:   rate.AskPrice.String(),
+		BidPrice:   rate.BidPrice.String(),
+		Timestamp:  rate.Timestamp,
+		AskPartial: rate.AskPartial,
+		BidPartial: rate.BidPartial,
+		Method:     methodToProto(rate.Method),
+		MidPrice:   rate.MidPrice.String(),
+		Spread:     rate.Spread.String(),
+		SpreadBps:  rate.SpreadBps.String(),
+		Source:     rate.Source,
+		Venues:     rate.Venues,
+		Cached:     rate.Cached,
+		CacheAgeMs: rate.CacheAge.Milliseconds(),
+	}
+}
+
+// ohlcToProto converts the prices of a candle to their protobuf message.
+func ohlcToProto(prices models.OHLC) *pb.OHLC {
+	return &pb.OHLC{
+		Open:  prices.Open.String(),
+		High:  prices.High.String(),
+		Low:   prices.Low.String(),
+		Close: prices.Close.String(),
+	}
+}
+
+// methodFromProto converts a protobuf calculation method to the model one.
+// An unspecified method is converted to an empty one to let the service pick the default.
+func methodFromProto(method pb.CalculationMethod) (models.CalculationMethod, bool) {
+	switch method {
+	case pb.CalculationMethod_CALCULATION_METHOD_UNSPECIFIED:
+		return "", true
+	case pb.CalculationMethod_CALCULATION_METHOD_TOP:
+		return models.MethodTop, true
+	case pb.CalculationMethod_CALCULATION_METHOD_TOP_N:
+		return models.MethodTopN, true
+	case pb.CalculationMethod_CALCULATION_METHOD_AVG_NM:
+		return models.MethodAvgNM, true
+	case pb.CalculationMethod_CALCULATION_METHOD_VWAP:
+		return models.MethodVWAP, true
+	default:
+		return "", false
+	}
+}
+
+// methodToProto converts a model calculation method to the protobuf one.
+func methodToProto(method models.CalculationMethod) pb.CalculationMethod {
+	switch method {
+	case models.MethodTop:
+		return pb.CalculationMethod_CALCULATION_METHOD_TOP
+	case models.MethodTopN:
+		return pb.CalculationMethod_CALCULATION_METHOD_TOP_N
+	case models.MethodAvgNM:
+		return pb.CalculationMethod_CALCULATION_METHOD_AVG_NM
+	case models.MethodVWAP:
+		return pb.CalculationMethod_CALCULATION_METHOD_VWAP
+	default:
+		return pb.CalculationMethod_CALCULATION_METHOD_UNSPECIFIED
+	}
 }
@@ -0,0 +1,289 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	handler "usdt-rate-service/internal/handler/grpc"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/pb"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/internal/service"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRatesHandler_GetRates_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		depthErr   error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name:       "unknown market",
+			depthErr:   fmt.Errorf("%w: grinex API error: 404 Not Found", models.ErrUnknownMarket),
+			wantCode:   codes.NotFound,
+			wantReason: handler.ReasonUnknownMarket,
+		},
+		{
+			name:       "upstream timeout",
+			depthErr:   fmt.Errorf("%w: context deadline exceeded", models.ErrUpstreamTimeout),
+			wantCode:   codes.DeadlineExceeded,
+			wantReason: handler.ReasonUpstreamTimeout,
+		},
+		{
+			name: "outage behind the fallback chain",
+			depthErr: fmt.Errorf("%w: %w", models.ErrAllProvidersFailed, errors.Join(
+				fmt.Errorf("rest: %w: example answered 404 Not Found", models.ErrUnknownMarket),
+				fmt.Errorf("grinex: %w: 502 Bad Gateway", models.ErrUpstreamUnavailable),
+			)),
+			wantCode:   codes.Unavailable,
+			wantReason: handler.ReasonAllProvidersFailed,
+		},
+		{
+			name:       "circuit open",
+			depthErr:   fmt.Errorf("%w: grinex for market usdtrub", models.ErrCircuitOpen),
+			wantCode:   codes.Unavailable,
+			wantReason: handler.ReasonCircuitOpen,
+		},
+		{
+			name:       "invalid data",
+			depthErr:   fmt.Errorf("asks[0].price: %w", models.ErrInvalidDecimal),
+			wantCode:   codes.FailedPrecondition,
+			wantReason: handler.ReasonInvalidDepth,
+		},
+		{
+			name:       "unexpected error",
+			depthErr:   errors.New("boom"),
+			wantCode:   codes.Internal,
+			wantReason: handler.ReasonInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depthProvider := mocks.NewMockDepthProvider(t)
+			depthProvider.On("GetDepth", mock.Anything, "usdtrub").Return(nil, tt.depthErr)
+			repo := mocks.NewMockRatesRepository(t)
+			h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), depthProvider, repo))
+
+			_, err := h.GetRates(context.Background(), &pb.GetRatesRequest{Market: "usdtrub"})
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantCode, st.Code())
+			if tt.wantCode == codes.Internal {
+				assert.Equal(t, "failed to get rates", st.Message())
+			}
+
+			require.Len(t, st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantReason, info.GetReason())
+			assert.Equal(t, handler.ErrorDomain, info.GetDomain())
+			assert.Equal(t, "usdtrub", info.GetMetadata()["market"])
+		})
+	}
+}
+
+func TestRatesHandler_GetRates_Fallback(t *testing.T) {
+	ctx := context.Background()
+	grinex := mocks.NewMockDepthProvider(t)
+	grinex.On("GetDepth", mock.Anything, "usdtrbu").
+		Return(nil, fmt.Errorf("%w: grinex API error: 404 Not Found", models.ErrUnknownMarket))
+	fallback := provider.NewFallback(zap.NewNop(), []provider.Named{{Name: "grinex", Provider: grinex}})
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), fallback, mocks.NewMockRatesRepository(t)))
+
+	_, err := h.GetRates(ctx, &pb.GetRatesRequest{Market: "usdtrbu"})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, handler.ReasonUnknownMarket, info.GetReason())
+}
+
+func TestRatesHandler_ListMarkets(t *testing.T) {
+	ctx := context.Background()
+	markets := mocks.NewMockMarketRegistry(t)
+	markets.On("ListMarkets", ctx).
+		Return([]models.Market{{ID: "usdtrub", Name: "USDT/RUB", Base: "usdt", Quote: "rub"}}, nil)
+	markets.On("ValidateMarket", ctx, "usdtrbu").Return(fmt.Errorf("%w: %q", models.ErrUnsupportedMarket, "usdtrbu"))
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, nil, service.WithMarketRegistry(markets)))
+
+	resp, err := h.ListMarkets(ctx, &pb.ListMarketsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetMarkets(), 1)
+	assert.Equal(t, "usdtrub", resp.GetMarkets()[0].GetId())
+	assert.Equal(t, "USDT/RUB", resp.GetMarkets()[0].GetName())
+
+	_, err = h.GetRates(ctx, &pb.GetRatesRequest{Market: "usdtrbu"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_GetTrades(t *testing.T) {
+	ctx := context.Background()
+	trades := mocks.NewMockTradesProvider(t)
+	trades.On("GetTrades", ctx, "usdtrub", 2).Return([]models.Trade{
+		{ID: 1, Price: models.MustParseDecimal("80.1"), Volume: models.MustParseDecimal("1"), Timestamp: 100},
+		{ID: 2, Price: models.MustParseDecimal("80.2"), Volume: models.MustParseDecimal("1"), Timestamp: 200},
+	}, nil)
+	trades.On("GetTrades", ctx, "btcusdt", service.DefaultTradesLimit).Return(nil, nil)
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, nil, service.WithTrades(trades, nil)))
+
+	resp, err := h.GetTrades(ctx, &pb.GetTradesRequest{Market: "usdtrub", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, resp.GetTrades(), 2)
+	assert.Equal(t, int64(2), resp.GetTrades()[0].GetId())
+	assert.Equal(t, "80.2", resp.GetLastPrice())
+	assert.Equal(t, int64(200), resp.GetLastTimestamp())
+
+	_, err = h.GetTrades(ctx, &pb.GetTradesRequest{Market: "btcusdt"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.GetTrades(ctx, &pb.GetTradesRequest{Market: "usdtrub", Limit: service.MaxTradesLimit + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_GetRateHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockRatesRepository(t)
+	repo.On("GetRateHistory", ctx, models.RateHistoryQuery{Market: "usdtrub", From: 100, Limit: 2}).
+		Return([]models.StoredRate{
+			{ID: 2, Rate: models.Rate{AskPrice: models.MustParseDecimal("80.5"), Timestamp: 200, Source: "grinex"}},
+			{ID: 1, Rate: models.Rate{AskPrice: models.MustParseDecimal("80.4"), Timestamp: 100, Source: "grinex"}},
+		}, nil)
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, repo))
+
+	resp, err := h.GetRateHistory(ctx, &pb.GetRateHistoryRequest{Market: "usdtrub", From: 100, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRates(), 1)
+	assert.Equal(t, "80.5", resp.GetRates()[0].GetAskPrice())
+	assert.NotEmpty(t, resp.GetNextCursor())
+
+	_, err = h.GetRateHistory(ctx, &pb.GetRateHistoryRequest{Market: "usdtrub", Cursor: "???"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.GetRateHistory(ctx, &pb.GetRateHistoryRequest{Market: "usdtrub", Limit: service.MaxRateHistoryLimit + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.GetRateHistory(ctx, &pb.GetRateHistoryRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_GetCandles(t *testing.T) {
+	ctx := context.Background()
+	prices := models.OHLC{
+		Open:  models.MustParseDecimal("80.1"),
+		High:  models.MustParseDecimal("80.9"),
+		Low:   models.MustParseDecimal("80"),
+		Close: models.MustParseDecimal("80.5"),
+	}
+	repo := mocks.NewMockRatesRepository(t)
+	repo.On("GetCandles", ctx, models.CandleQuery{
+		Market: "usdtrub", Method: models.MethodTop, Interval: 3600, From: 3600, To: 7200,
+	}).
+		Return([]models.Candle{{Timestamp: 3600, Ask: prices, Bid: prices, Mid: prices, Count: 12}}, nil)
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, repo))
+
+	resp, err := h.GetCandles(ctx, &pb.GetCandlesRequest{Market: "usdtrub", Interval: "1h", From: 3600, To: 7200})
+	require.NoError(t, err)
+	require.Len(t, resp.GetCandles(), 1)
+	assert.Equal(t, "80.9", resp.GetCandles()[0].GetAsk().GetHigh())
+	assert.Equal(t, "80.5", resp.GetCandles()[0].GetMid().GetClose())
+	assert.Equal(t, int64(12), resp.GetCandles()[0].GetCount())
+
+	_, err = h.GetCandles(ctx, &pb.GetCandlesRequest{Market: "usdtrub", Interval: "1w", From: 3600})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.GetCandles(ctx, &pb.GetCandlesRequest{Interval: "1h", From: 3600})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.GetCandles(ctx, &pb.GetCandlesRequest{Market: "usdtrub", Interval: "1h", From: 3600, Method: 42})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_GetRateAsOf(t *testing.T) {
+	ctx := context.Background()
+	stored := &models.StoredRate{
+		ID:   42,
+		Rate: models.Rate{Market: "usdtrub", BidPrice: models.MustParseDecimal("80.1"), Timestamp: 1774990799},
+	}
+	repo := mocks.NewMockRatesRepository(t)
+	repo.On("GetRateAsOf", ctx, "usdtrub", models.MethodTop, int64(1774990799), int64(1774990799-3600)).
+		Return(stored, nil)
+	repo.On("GetRateAsOf", ctx, "usdtrub", models.MethodVWAP, int64(3600), int64(0)).
+		Return(nil, fmt.Errorf("%w: usdtrub", models.ErrRateNotFound))
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, repo,
+		service.WithRateAsOfMaxLookback(time.Hour)))
+
+	resp, err := h.GetRateAsOf(ctx, &pb.GetRateAsOfRequest{Market: "usdtrub", Timestamp: 1774990799})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), resp.GetId())
+	assert.Equal(t, "80.1", resp.GetRate().GetBidPrice())
+
+	_, err = h.GetRateAsOf(ctx, &pb.GetRateAsOfRequest{
+		Market: "usdtrub", Timestamp: 3600, Method: pb.CalculationMethod_CALCULATION_METHOD_VWAP,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.GetRateAsOf(ctx, &pb.GetRateAsOfRequest{Timestamp: 3600})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_GetLatestRate(t *testing.T) {
+	ctx := context.Background()
+	stored := &models.StoredRate{
+		ID:   42,
+		Rate: models.Rate{Market: "usdtrub", AskPrice: models.MustParseDecimal("80.5"), Timestamp: time.Now().Unix()},
+	}
+	repo := mocks.NewMockRatesRepository(t)
+	repo.On("GetLatestRate", ctx, "usdtrub", models.MethodTop).Return(stored, nil)
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, repo))
+
+	resp, err := h.GetLatestRate(ctx, &pb.GetLatestRateRequest{Market: "usdtrub", MaxAgeMs: 60000})
+	require.NoError(t, err)
+	assert.True(t, resp.GetStored())
+	assert.Equal(t, "80.5", resp.GetRate().GetAskPrice())
+	assert.Less(t, resp.GetAgeMs(), int64(60000))
+
+	_, err = h.GetLatestRate(ctx, &pb.GetLatestRateRequest{Market: "usdtrub", MaxAgeMs: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.GetLatestRate(ctx, &pb.GetLatestRateRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRatesHandler_HealthCheck(t *testing.T) {
+	ctx := context.Background()
+	h := handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, nil))
+	resp, err := h.HealthCheck(ctx, &pb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.GetStatus())
+	assert.Empty(t, resp.GetCircuits())
+
+	since := time.Unix(1774990799, 0)
+	circuits := mocks.NewMockCircuitStatesProvider(t)
+	circuits.On("CircuitStates").Return([]models.CircuitState{
+		{Provider: "grinex", Market: "btcusdt", State: models.CircuitClosed, Since: since},
+		{Provider: "grinex", Market: "usdtrub", State: "open", Since: since},
+	})
+	h = handler.NewRatesHandler(service.NewRatesService(zap.NewNop(), nil, nil, service.WithCircuitStates(circuits)))
+	resp, err = h.HealthCheck(ctx, &pb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "degraded", resp.GetStatus())
+	require.Len(t, resp.GetCircuits(), 2)
+	assert.Equal(t, "usdtrub", resp.GetCircuits()[1].GetMarket())
+	assert.Equal(t, "open", resp.GetCircuits()[1].GetState())
+	assert.Equal(t, int64(1774990799), resp.GetCircuits()[1].GetSince())
+}
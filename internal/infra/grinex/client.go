@@ -3,31 +3,92 @@ package grinex
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	getDepthEndpoint    = "/api/v2/depth"
+	listMarketsEndpoint = "/api/v2/markets"
+	getTradesEndpoint   = "/api/v2/trades"
+
+	// DefaultUserAgent is the User-Agent header sent unless another one is set with WithHeaders.
+	DefaultUserAgent = "usdt-rate-service"
+	// DefaultMaxBodySize is the size of the largest response body read unless another one is set with WithMaxBodySize.
+	DefaultMaxBodySize = 10 << 20
 )
 
-const getDepthEndpoint = "/api/v2/depth"
+// ErrResponseTooLarge is returned when a response body exceeds the maximum size of the Client.
+var ErrResponseTooLarge = errors.New("grinex response too large")
 
 // Client represents a Grinex API client.
 // It contains the base URL for the API and an HTTP client for making requests.
 type Client struct {
-	baseURL    *url.URL
-	httpClient *http.Client
+	baseURL     *url.URL
+	httpClient  *http.Client
+	transport   *http.Transport
+	headers     http.Header
+	logger      *zap.Logger
+	retryPolicy RetryPolicy
+	maxBodySize int64
+
+	limiter       *rate.Limiter
+	rateLimitMode RateLimitMode
+}
+
+// Option configures optional Client settings.
+type Option func(*Client)
+
+// WithLogger sets the logger used to report request attempts.
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. By default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithMaxBodySize limits the size of the response bodies read, so that a misbehaving server cannot
+// exhaust the memory. Zero means no limit.
+func WithMaxBodySize(size int64) Option {
+	return func(c *Client) {
+		c.maxBodySize = size
+	}
 }
 
 // NewClient creates a new Grinex API client.
-func NewClient(baseURL string) (*Client, error) {
+// Without options it uses the settings of http.DefaultTransport and no overall timeout.
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
-	httpClient := &http.Client{}
-	return &Client{
-		baseURL:    parsedURL,
-		httpClient: httpClient,
-	}, nil
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	c := &Client{
+		baseURL:     parsedURL,
+		transport:   transport.Clone(),
+		headers:     http.Header{"User-Agent": {DefaultUserAgent}},
+		logger:      zap.NewNop(),
+		maxBodySize: DefaultMaxBodySize,
+	}
+	c.httpClient = &http.Client{Transport: c.transport}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
 
 // GetDepth retrieves the depth of the specified market from the Grinex API.
@@ -42,25 +103,142 @@ func (c *Client) GetDepth(ctx context.Context, market string) (*DepthResponse, e
 	query.Set("market", market)
 	depthURL.RawQuery = query.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, depthURL.String(), nil)
+	var depthResponse DepthResponse
+	if err = c.getJSON(ctx, depthURL.String(), &depthResponse); err != nil {
+		return nil, err
+	}
+
+	return &depthResponse, nil
+}
+
+// GetTrades retrieves the most recent trades of the specified market from the Grinex API, newest first.
+// A positive limit caps the number of trades, otherwise the exchange default applies.
+func (c *Client) GetTrades(ctx context.Context, market string, limit int) ([]Trade, error) {
+	tradesURL, err := c.baseURL.Parse(getTradesEndpoint)
 	if err != nil {
 		return nil, err
 	}
+	query := tradesURL.Query()
+	query.Set("market", market)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	tradesURL.RawQuery = query.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	var trades list[Trade]
+	if err = c.getJSON(ctx, tradesURL.String(), &trades); err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+// ListMarkets retrieves the markets listed by the exchange.
+func (c *Client) ListMarkets(ctx context.Context) ([]Market, error) {
+	marketsURL, err := c.baseURL.Parse(listMarketsEndpoint)
 	if err != nil {
 		return nil, err
 	}
+
+	var markets list[Market]
+	if err = c.getJSON(ctx, marketsURL.String(), &markets); err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// getJSON sends a GET request and decodes the JSON response into out.
+// Failed attempts are retried according to the retry policy as long as the context allows it.
+// A retry refused by the rate limiter returns the error of the previous attempt, so that an upstream
+// failure is not reported as local rate limiting.
+func (c *Client) getJSON(ctx context.Context, rawURL string, out any) error {
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		logger := c.logger.With(zap.String("url", rawURL), zap.Int("attempt", attempt))
+		logger.Debug("Sending Grinex request")
+
+		err := c.waitForToken(ctx)
+		if errors.Is(err, ErrRateLimited) && lastErr != nil {
+			logger.With(bodyFields(lastErr)...).Warn("Grinex request failed, retry rate limited",
+				zap.NamedError("limiter", err), zap.Error(lastErr))
+			return lastErr
+		}
+		if err == nil {
+			err = c.doJSON(ctx, rawURL, out)
+		}
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		logger = logger.With(bodyFields(err)...)
+		if attempt >= c.retryPolicy.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			logger.Warn("Grinex request failed", zap.Error(err))
+			return err
+		}
+
+		wait := retryAfter(err)
+		if c.retryPolicy.MaxBackoff > 0 && wait > c.retryPolicy.MaxBackoff {
+			logger.Warn("Grinex request failed, retry requested after the maximum backoff",
+				zap.Duration("retryAfter", wait), zap.Error(err))
+			return err
+		}
+		delay := max(c.retryPolicy.backoff(attempt), wait)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			logger.Warn("Grinex request failed, no time left to retry", zap.Duration("delay", delay), zap.Error(err))
+			return err
+		}
+		logger.Warn("Grinex request failed, retrying", zap.Duration("delay", delay), zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// doJSON makes a single GET request and decodes the JSON response into out.
+func (c *Client) doJSON(ctx context.Context, rawURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+		return newAPIError(resp)
 	}
 
-	var depthResponse DepthResponse
-	if err = json.NewDecoder(resp.Body).Decode(&depthResponse); err != nil {
-		return nil, err
+	body := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		body = io.LimitReader(resp.Body, c.maxBodySize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if c.maxBodySize > 0 && int64(len(data)) > c.maxBodySize {
+		return fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, c.maxBodySize)
 	}
+	return json.Unmarshal(data, out)
+}
 
-	return &depthResponse, nil
+// bodyFields returns the log field of the response body of an APIError, which its message leaves out.
+func bodyFields(err error) []zap.Field {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Body != "" {
+		return []zap.Field{zap.String("body", apiErr.Body)}
+	}
+	return nil
 }
@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func newTestClient(handler http.Handler) *Client {
@@ -21,6 +24,7 @@ func newTestClient(handler http.Handler) *Client {
 	return &Client{
 		baseURL:    parsedURL,
 		httpClient: server.Client(),
+		logger:     zap.NewNop(),
 	}
 }
 
@@ -69,6 +73,7 @@ func TestGetDepth_TableDriven(t *testing.T) {
 			client: &Client{
 				baseURL:    &url.URL{Scheme: "http", Host: "invalid"},
 				httpClient: &http.Client{Transport: nil},
+				logger:     zap.NewNop(),
 			},
 			wantErr: true,
 		},
@@ -77,6 +82,7 @@ func TestGetDepth_TableDriven(t *testing.T) {
 			client: &Client{
 				baseURL:    &url.URL{Scheme: "http", Host: "%%invalid%%"},
 				httpClient: http.DefaultClient,
+				logger:     zap.NewNop(),
 			},
 			wantErr: true,
 		},
@@ -104,3 +110,299 @@ func TestGetDepth_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDepth_Retry(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2,
+	}
+
+	// sequence returns a handler that answers with the given status codes in turn and then with a valid depth.
+	sequence := func(calls *atomic.Int32, statuses ...int) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			call := int(calls.Add(1)) - 1
+			if call < len(statuses) {
+				w.WriteHeader(statuses[call])
+				return
+			}
+			_ = json.NewEncoder(w).Encode(DepthResponse{Timestamp: 1})
+		}
+	}
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "retries 5xx", statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable}, wantCalls: 3},
+		{name: "retries 429", statuses: []int{http.StatusTooManyRequests}, wantCalls: 2},
+		{name: "does not retry 4xx", statuses: []int{http.StatusBadRequest}, wantCalls: 1, wantErr: true},
+		{
+			name:      "gives up after max attempts",
+			statuses:  []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			client := newTestClient(sequence(&calls, tt.statuses...))
+			client.retryPolicy = policy
+
+			_, err := client.GetDepth(context.Background(), "usdtrub")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(DepthResponse{Timestamp: 1})
+		}))
+		client.retryPolicy = policy
+		client.retryPolicy.MaxBackoff = 2 * time.Second
+
+		start := time.Now()
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("gives up if Retry-After exceeds the maximum backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		client.retryPolicy = policy
+
+		start := time.Now()
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, time.Hour, apiErr.RetryAfter)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("respects the context deadline", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		client.retryPolicy = policy
+		client.retryPolicy.MaxBackoff = time.Minute
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := client.GetDepth(ctx, "usdtrub")
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, Jitter: 0.5}
+	for attempt, maxDelay := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		6: time.Second,
+	} {
+		delay := policy.backoff(attempt)
+		assert.LessOrEqual(t, delay, maxDelay)
+		assert.GreaterOrEqual(t, delay, maxDelay/2)
+	}
+}
+
+func TestGetDepth_RateLimit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(DepthResponse{Timestamp: time.Now().Unix()})
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		client := newTestClient(handler)
+		WithRateLimit(1, 2, RateLimitFailFast)(client)
+
+		for range 2 {
+			_, err := client.GetDepth(context.Background(), "usdtrub")
+			require.NoError(t, err)
+		}
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("wait queues until a token is available", func(t *testing.T) {
+		client := newTestClient(handler)
+		WithRateLimit(20, 1, RateLimitWait)(client)
+
+		start := time.Now()
+		for range 3 {
+			_, err := client.GetDepth(context.Background(), "usdtrub")
+			require.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("wait does not outlive the context deadline", func(t *testing.T) {
+		client := newTestClient(handler)
+		WithRateLimit(0.1, 1, RateLimitWait)(client)
+
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = client.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("rate limited retry returns the upstream error", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		client.retryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+		WithRateLimit(0.1, 1, RateLimitFailFast)(client)
+
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		assert.NotErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("zero rate disables the limit", func(t *testing.T) {
+		client := newTestClient(handler)
+		WithRateLimit(0, 0, RateLimitFailFast)(client)
+
+		for range 5 {
+			_, err := client.GetDepth(context.Background(), "usdtrub")
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestGetDepth_MaxBodySize(t *testing.T) {
+	body := `{"asks":[` + strings.Repeat(`{"price":"80.5","volume":"1"},`, 100) + `{"price":"80.5","volume":"1"}]}`
+
+	client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	client.maxBodySize = int64(len(body))
+	depth, err := client.GetDepth(context.Background(), "usdtrub")
+	require.NoError(t, err)
+	assert.Len(t, depth.Asks, 101)
+
+	client.maxBodySize = int64(len(body)) - 1
+	_, err = client.GetDepth(context.Background(), "usdtrub")
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestGetDepth_APIError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantCode      string
+		wantMessage   string
+		unknownMarket bool
+		temporary     bool
+	}{
+		{
+			name:          "errors list",
+			status:        http.StatusUnprocessableEntity,
+			body:          `{"errors":["market.does_not_exist"]}`,
+			wantCode:      "market.does_not_exist",
+			unknownMarket: true,
+		},
+		{
+			name:        "error object with numeric code",
+			status:      http.StatusBadRequest,
+			body:        `{"error":{"code":2001,"message":"market is disabled"}}`,
+			wantCode:    "2001",
+			wantMessage: "market is disabled",
+		},
+		{name: "plain text outage", status: http.StatusBadGateway, body: "<html>proxy error</html>", temporary: true},
+		{name: "not found", status: http.StatusNotFound, body: "", unknownMarket: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+
+			_, err := client.GetDepth(context.Background(), "usdtrub")
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.body, apiErr.Body)
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+			assert.Equal(t, tt.wantMessage, apiErr.Message)
+			assert.Equal(t, tt.unknownMarket, apiErr.UnknownMarket())
+			assert.Equal(t, tt.temporary, apiErr.Temporary())
+			if tt.body != "" {
+				assert.NotContains(t, err.Error(), tt.body)
+			}
+		})
+	}
+
+	t.Run("truncates the body", func(t *testing.T) {
+		client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, strings.Repeat("x", 2*maxErrorBodySize))
+		}))
+
+		_, err := client.GetDepth(context.Background(), "usdtrub")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Len(t, apiErr.Body, maxErrorBodySize)
+	})
+}
+
+func TestListMarkets(t *testing.T) {
+	client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, listMarketsEndpoint, r.URL.Path)
+		_, _ = io.WriteString(w, `[{"id":"usdtrub","name":"USDT/RUB","base_unit":"usdt","quote_unit":"rub"}]`)
+	}))
+
+	markets, err := client.ListMarkets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Market{{ID: "usdtrub", Name: "USDT/RUB", BaseUnit: "usdt", QuoteUnit: "rub"}}, markets)
+}
+
+func TestGetTrades(t *testing.T) {
+	client := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, getTradesEndpoint, r.URL.Path)
+		assert.Equal(t, "usdtrub", r.URL.Query().Get("market"))
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		_, _ = io.WriteString(w, `[{"id":7,"price":"80.5","volume":"10","funds":"805","market":"usdtrub",`+
+			`"created_at":"2025-01-26T14:20:34Z","taker_type":"buy"}]`)
+	}))
+
+	trades, err := client.GetTrades(context.Background(), "usdtrub", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Trade{{
+		ID: 7, Price: "80.5", Volume: "10", Funds: "805", Market: "usdtrub",
+		CreatedAt: "2025-01-26T14:20:34Z", TakerType: "buy",
+	}}, trades)
+}
@@ -1,9 +1,45 @@
 package grinex
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The DTOs are decoded leniently: numeric fields are accepted both as JSON strings and as numbers
+// and kept as their literal text, missing and null fields are left empty. A field of any other type
+// fails the decoding with a *FieldError locating it.
+
 type DepthResponse struct {
 	Timestamp int64   `json:"timestamp"`
 	Asks      []Order `json:"asks"`
 	Bids      []Order `json:"bids"`
+	// Sequence is the sequence number of the last book update included, if the exchange reports it.
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// UnmarshalJSON decodes a depth response leniently.
+func (r *DepthResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Timestamp json.RawMessage `json:"timestamp"`
+		Asks      json.RawMessage `json:"asks"`
+		Bids      json.RawMessage `json:"bids"`
+		Sequence  json.RawMessage `json:"sequence"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var d fieldDecoder
+	*r = DepthResponse{
+		Timestamp: d.int("timestamp", raw.Timestamp),
+		Asks:      decodeList[Order](&d, "asks", raw.Asks),
+		Bids:      decodeList[Order](&d, "bids", raw.Bids),
+		Sequence:  d.int("sequence", raw.Sequence),
+	}
+	return d.err
 }
 
 type Order struct {
@@ -13,3 +49,259 @@ type Order struct {
 	Factor string `json:"factor"`
 	Type   string `json:"type"`
 }
+
+// UnmarshalJSON decodes an order leniently.
+func (o *Order) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Price  json.RawMessage `json:"price"`
+		Volume json.RawMessage `json:"volume"`
+		Amount json.RawMessage `json:"amount"`
+		Factor json.RawMessage `json:"factor"`
+		Type   json.RawMessage `json:"type"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var d fieldDecoder
+	*o = Order{
+		Price:  d.string("price", raw.Price),
+		Volume: d.string("volume", raw.Volume),
+		Amount: d.string("amount", raw.Amount),
+		Factor: d.string("factor", raw.Factor),
+		Type:   d.string("type", raw.Type),
+	}
+	return d.err
+}
+
+// Market is a market listed by the exchange.
+type Market struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	BaseUnit  string `json:"base_unit"`
+	QuoteUnit string `json:"quote_unit"`
+}
+
+// UnmarshalJSON decodes a market leniently. A numeric identifier is kept as its literal text.
+func (m *Market) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        json.RawMessage `json:"id"`
+		Name      json.RawMessage `json:"name"`
+		BaseUnit  json.RawMessage `json:"base_unit"`
+		QuoteUnit json.RawMessage `json:"quote_unit"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var d fieldDecoder
+	*m = Market{
+		ID:        d.string("id", raw.ID),
+		Name:      d.string("name", raw.Name),
+		BaseUnit:  d.string("base_unit", raw.BaseUnit),
+		QuoteUnit: d.string("quote_unit", raw.QuoteUnit),
+	}
+	return d.err
+}
+
+// Trade is an executed trade of a market.
+type Trade struct {
+	ID        int64  `json:"id"`
+	Price     string `json:"price"`
+	Volume    string `json:"volume"`
+	Funds     string `json:"funds"`
+	Market    string `json:"market"`
+	CreatedAt string `json:"created_at"`
+	TakerType string `json:"taker_type"`
+}
+
+// UnmarshalJSON decodes a trade leniently. A numeric creation time is kept as its literal text.
+func (t *Trade) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        json.RawMessage `json:"id"`
+		Price     json.RawMessage `json:"price"`
+		Volume    json.RawMessage `json:"volume"`
+		Funds     json.RawMessage `json:"funds"`
+		Market    json.RawMessage `json:"market"`
+		CreatedAt json.RawMessage `json:"created_at"`
+		TakerType json.RawMessage `json:"taker_type"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var d fieldDecoder
+	*t = Trade{
+		ID:        d.int("id", raw.ID),
+		Price:     d.string("price", raw.Price),
+		Volume:    d.string("volume", raw.Volume),
+		Funds:     d.string("funds", raw.Funds),
+		Market:    d.string("market", raw.Market),
+		CreatedAt: d.string("created_at", raw.CreatedAt),
+		TakerType: d.string("taker_type", raw.TakerType),
+	}
+	return d.err
+}
+
+// BookUpdate is an order book snapshot or increment of the WebSocket stream.
+type BookUpdate struct {
+	Asks     PriceLevels `json:"asks"`
+	Bids     PriceLevels `json:"bids"`
+	Sequence int64       `json:"sequence"`
+}
+
+// PriceLevels are the price and volume pairs of one side of a BookUpdate.
+// In an increment an empty or zero volume removes the level from the book.
+type PriceLevels [][]string
+
+// UnmarshalJSON decodes either a list of pairs or, as increments often carry, a single pair.
+// Prices and volumes may be strings or numbers.
+func (l *PriceLevels) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	if len(raws) > 0 && !bytes.HasPrefix(bytes.TrimSpace(raws[0]), []byte("[")) {
+		raws = []json.RawMessage{data}
+	}
+
+	var d fieldDecoder
+	levels := make(PriceLevels, len(raws))
+	for i, raw := range raws {
+		levels[i] = decodeList[string](&d, fmt.Sprintf("[%d]", i), raw)
+	}
+	*l = levels
+	return d.err
+}
+
+// list is a JSON array of DTOs whose decoding errors report the index of the malformed element.
+type list[T any] []T
+
+// UnmarshalJSON decodes the array.
+func (l *list[T]) UnmarshalJSON(data []byte) error {
+	var d fieldDecoder
+	*l = decodeList[T](&d, "", data)
+	return d.err
+}
+
+// FieldError reports a malformed field of a response.
+type FieldError struct {
+	// Path locates the field in the response, e.g. "asks[2].price".
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldDecoder decodes the fields of a DTO and keeps the first error.
+// Once it has failed, the following fields are left empty.
+type fieldDecoder struct {
+	err error
+}
+
+// string decodes a string or number field into its text.
+func (d *fieldDecoder) string(name string, raw json.RawMessage) string {
+	if d.err != nil {
+		return ""
+	}
+	value, err := decodeText(raw)
+	if err != nil {
+		d.fail(name, err)
+	}
+	return value
+}
+
+// int decodes an integer field given as a number or a numeric string.
+func (d *fieldDecoder) int(name string, raw json.RawMessage) int64 {
+	text := d.string(name, raw)
+	if d.err != nil || text == "" {
+		return 0
+	}
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i
+	}
+	// Numbers such as 1.7e9 are integers too.
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) >= math.MaxInt64 {
+		d.fail(name, fmt.Errorf("expected an integer, got %s", truncate(text)))
+		return 0
+	}
+	return int64(f)
+}
+
+// fail records the error of a field. The path of an error of a nested DTO is appended to the field name.
+func (d *fieldDecoder) fail(name string, err error) {
+	fieldErr, ok := err.(*FieldError) //nolint:errorlint // Only the error of a nested DTO itself carries a path.
+	if !ok {
+		d.err = &FieldError{Path: name, Err: err}
+		return
+	}
+	path := fieldErr.Path
+	if name != "" && !strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+	d.err = &FieldError{Path: name + path, Err: fieldErr.Err}
+}
+
+// decodeList decodes an array field element by element, so that an error reports the index.
+// A missing or null field is decoded as nil.
+func decodeList[T any](d *fieldDecoder, name string, raw json.RawMessage) []T {
+	if d.err != nil || isNull(raw) {
+		return nil
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(raw, &raws); err != nil {
+		d.fail(name, fmt.Errorf("expected an array, got %s", truncate(string(raw))))
+		return nil
+	}
+	items := make([]T, len(raws))
+	for i, item := range raws {
+		if text, ok := any(&items[i]).(*string); ok {
+			*text = d.string(fmt.Sprintf("%s[%d]", name, i), item)
+		} else if err := json.Unmarshal(item, &items[i]); err != nil {
+			d.fail(fmt.Sprintf("%s[%d]", name, i), err)
+		}
+		if d.err != nil {
+			return nil
+		}
+	}
+	return items
+}
+
+// decodeText decodes a JSON string or number into its text. Missing and null values are decoded as "".
+func decodeText(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case isNull(raw):
+		return "", nil
+	case raw[0] == '"':
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	case raw[0] == '-' || (raw[0] >= '0' && raw[0] <= '9'):
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return "", err
+		}
+		return n.String(), nil
+	default:
+		return "", fmt.Errorf("expected a string or a number, got %s", truncate(string(raw)))
+	}
+}
+
+func isNull(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// truncate shortens a malformed value quoted in an error.
+func truncate(s string) string {
+	const maxLen = 32
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
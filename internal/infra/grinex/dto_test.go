@@ -0,0 +1,179 @@
+//nolint:testpackage // This file needs to be in the same package because we are testing the package internals.
+package grinex
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepthResponse_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     DepthResponse
+		wantPath string
+	}{
+		{
+			name: "string fields",
+			body: `{"timestamp":1737901234,` +
+				`"asks":[{"price":"80.5","volume":"10","amount":"805","factor":"0.01","type":"limit"}]}`,
+			want: DepthResponse{
+				Timestamp: 1737901234,
+				Asks:      []Order{{Price: "80.5", Volume: "10", Amount: "805", Factor: "0.01", Type: "limit"}},
+			},
+		},
+		{
+			name: "numeric fields",
+			body: `{"timestamp":"1737901234","asks":[{"price":80.50,"volume":1e1}],` +
+				`"bids":[{"price":80.1,"volume":2}],"sequence":1.2e1}`,
+			want: DepthResponse{
+				Timestamp: 1737901234,
+				Asks:      []Order{{Price: "80.50", Volume: "1e1"}},
+				Bids:      []Order{{Price: "80.1", Volume: "2"}},
+				Sequence:  12,
+			},
+		},
+		{
+			name: "missing and null fields",
+			body: `{"asks":null,"bids":[{"price":"80.1","volume":"2","factor":null}]}`,
+			want: DepthResponse{Bids: []Order{{Price: "80.1", Volume: "2"}}},
+		},
+		{
+			name:     "malformed order field",
+			body:     `{"asks":[{"price":"80.5","volume":"1"},{"price":"80.6","volume":"1"},{"price":{"value":80.7}}]}`,
+			wantPath: "asks[2].price",
+		},
+		{
+			name:     "malformed order",
+			body:     `{"bids":["80.1"]}`,
+			wantPath: "bids[0]",
+		},
+		{
+			name:     "side is not an array",
+			body:     `{"asks":{}}`,
+			wantPath: "asks",
+		},
+		{
+			name:     "fractional timestamp",
+			body:     `{"timestamp":1737901234.5}`,
+			wantPath: "timestamp",
+		},
+		{
+			name:     "boolean sequence",
+			body:     `{"sequence":true}`,
+			wantPath: "sequence",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got DepthResponse
+			err := json.Unmarshal([]byte(tt.body), &got)
+			if tt.wantPath != "" {
+				var fieldErr *FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, tt.wantPath, fieldErr.Path)
+				assert.True(t, strings.HasPrefix(err.Error(), tt.wantPath+": "), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTrade_UnmarshalJSON(t *testing.T) {
+	var trades list[Trade]
+	err := json.Unmarshal([]byte(`[
+		{"id":"7","price":80.5,"volume":"2","market":"usdtrub","created_at":1737901234,"taker_type":"buy"},
+		{"id":6,"price":"80.4","volume":3}
+	]`), &trades)
+	require.NoError(t, err)
+	assert.Equal(t, list[Trade]{
+		{ID: 7, Price: "80.5", Volume: "2", Market: "usdtrub", CreatedAt: "1737901234", TakerType: "buy"},
+		{ID: 6, Price: "80.4", Volume: "3"},
+	}, trades)
+
+	err = json.Unmarshal([]byte(`[{"id":1},{"id":"abc"}]`), &trades)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[1].id", fieldErr.Path)
+}
+
+func TestPriceLevels_UnmarshalJSON(t *testing.T) {
+	var update BookUpdate
+	require.NoError(t, json.Unmarshal([]byte(`{"asks":[80.5,"0"],"bids":[["80.1",2],[80.2,""]]}`), &update))
+	assert.Equal(t, PriceLevels{{"80.5", "0"}}, update.Asks)
+	assert.Equal(t, PriceLevels{{"80.1", "2"}, {"80.2", ""}}, update.Bids)
+
+	err := json.Unmarshal([]byte(`{"bids":[["80.1",2],["80.2",[1]]]}`), &update)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[1][1]", fieldErr.Path)
+}
+
+func TestMarket_UnmarshalJSON(t *testing.T) {
+	var markets list[Market]
+	err := json.Unmarshal([]byte(`[
+		{"id":"usdtrub","name":"USDT/RUB","base_unit":"usdt","quote_unit":"rub"},
+		{"id":42,"name":null,"base_unit":"btc"}
+	]`), &markets)
+	require.NoError(t, err)
+	assert.Equal(t, list[Market]{
+		{ID: "usdtrub", Name: "USDT/RUB", BaseUnit: "usdt", QuoteUnit: "rub"},
+		{ID: "42", BaseUnit: "btc"},
+	}, markets)
+
+	err = json.Unmarshal([]byte(`[{"id":"usdtrub"},{"id":"btcusdt","quote_unit":["usdt"]}]`), &markets)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "[1].quote_unit", fieldErr.Path)
+}
+
+// FuzzDepthResponse checks that decoding never panics and that a decoded response survives a round trip.
+func FuzzDepthResponse(f *testing.F) {
+	f.Add(`{"timestamp":1737901234,"asks":[{"price":"80.5","volume":"10","amount":"805","type":"limit"}],"bids":[]}`)
+	f.Add(`{"timestamp":"1737901234","asks":[{"price":80.5,"volume":1e1}],"sequence":12}`)
+	f.Add(`{"asks":null,"bids":[{"price":null,"factor":"0.01"}]}`)
+	f.Add(`{"asks":[{"price":{"value":1}}]}`)
+	f.Add(`{"timestamp":1.5e300}`)
+	f.Add(`[]`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		var depth DepthResponse
+		if json.Unmarshal([]byte(body), &depth) != nil {
+			return
+		}
+		data, err := json.Marshal(depth)
+		require.NoError(t, err)
+		var decoded DepthResponse
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, depth, decoded)
+	})
+}
+
+// FuzzTrade checks that decoding never panics and that a decoded trade survives a round trip.
+func FuzzTrade(f *testing.F) {
+	f.Add(`{"id":7,"price":"80.5","volume":"2","funds":"161","market":"usdtrub",` +
+		`"created_at":"2025-01-26T14:20:34Z","taker_type":"buy"}`)
+	f.Add(`{"id":"7","price":80.5,"volume":2,"created_at":1737901234}`)
+	f.Add(`{"id":"seven"}`)
+	f.Add(`{"id":-9223372036854775808}`)
+	f.Add(`null`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		var trade Trade
+		if json.Unmarshal([]byte(body), &trade) != nil {
+			return
+		}
+		data, err := json.Marshal(trade)
+		require.NoError(t, err)
+		var decoded Trade
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, trade, decoded)
+	})
+}
@@ -0,0 +1,98 @@
+package grinex
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize is the number of bytes of an error response body kept in APIError.
+const maxErrorBodySize = 512
+
+// APIError is returned when the Grinex API answers with a non-200 status.
+type APIError struct {
+	// StatusCode is the HTTP status code, e.g. 404.
+	StatusCode int
+	// Status is the HTTP status line, e.g. "404 Not Found".
+	Status string
+	// Code is the Grinex error code from the response body, empty if there is none.
+	Code string
+	// Message is the Grinex error message from the response body, empty if there is none.
+	Message string
+	// Body is the beginning of the response body, truncated to maxErrorBodySize bytes.
+	// It is logged by the Client but left out of the error message, which may reach API clients.
+	Body string
+	// RetryAfter is the delay requested in the Retry-After header, zero if there is none.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := "grinex API error: " + e.Status
+	if e.Code != "" {
+		msg += ", code " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Temporary reports whether the error is caused by an outage or throttling and the request may succeed later.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+// UnknownMarket reports whether Grinex rejected the request because it does not know the market,
+// either with a 404 status or with a market error code such as "market.does_not_exist".
+func (e *APIError) UnknownMarket() bool {
+	return e.StatusCode == http.StatusNotFound || strings.HasPrefix(e.Code, "market.")
+}
+
+// errorBody is the error payload of the Grinex API. Depending on the endpoint it is either
+// {"error": {"code": 2001, "message": "..."}} or {"errors": ["market.does_not_exist"]}.
+type errorBody struct {
+	Error *struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	} `json:"error"`
+	Errors []string `json:"errors"`
+}
+
+// newAPIError builds an APIError from a non-200 response, reading at most maxErrorBodySize bytes of its body.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	apiErr.Body = strings.TrimSpace(string(body))
+
+	var payload errorBody
+	if json.Unmarshal(body, &payload) != nil {
+		return apiErr
+	}
+	switch {
+	case payload.Error != nil:
+		apiErr.Code = rawCode(payload.Error.Code)
+		apiErr.Message = payload.Error.Message
+	case len(payload.Errors) > 0:
+		apiErr.Code = payload.Errors[0]
+	}
+	return apiErr
+}
+
+// rawCode returns a JSON error code, either a string or a number, as a string.
+func rawCode(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package grinex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned when a request is not sent because the local request budget is exhausted.
+var ErrRateLimited = errors.New("rate limited locally")
+
+// RateLimitMode is what the client does when the request budget is exhausted.
+type RateLimitMode string
+
+const (
+	// RateLimitWait queues the request until a token is available or the context deadline would pass.
+	RateLimitWait RateLimitMode = "wait"
+	// RateLimitFailFast fails the request with ErrRateLimited right away.
+	RateLimitFailFast RateLimitMode = "fail"
+)
+
+// WithRateLimit limits the client to rps requests per second with bursts of up to burst requests.
+// Every attempt counts, retries included. A zero rps disables the limit.
+func WithRateLimit(rps float64, burst int, mode RateLimitMode) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+		c.rateLimitMode = mode
+	}
+}
+
+// waitForToken takes a token from the limiter, waiting for one in RateLimitWait mode.
+// It returns an error wrapping ErrRateLimited if no token is available in time.
+func (c *Client) waitForToken(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if c.rateLimitMode == RateLimitFailFast {
+		if !c.limiter.Allow() {
+			return fmt.Errorf("%w: %g requests per second exceeded", ErrRateLimited, float64(c.limiter.Limit()))
+		}
+		return nil
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		// The limiter refuses to wait past the context deadline.
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package grinex
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried.
+// Only failures that are safe to retry for idempotent GET requests are retried:
+// network errors, 5xx responses and 429 Too Many Requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay. A response asking in its Retry-After header
+	// to wait longer than MaxBackoff is not retried.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after every retry.
+	Multiplier float64
+	// Jitter is the fraction of the delay, from 0 to 1, that is randomized to spread retries of many clients.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a retry policy with 3 attempts and a delay growing from 200ms up to 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// backoff returns the delay before the retry that follows the given attempt, starting from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for range attempt - 1 {
+		delay *= max(p.Multiplier, 1)
+	}
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	delay -= delay * min(max(p.Jitter, 0), 1) * rand.Float64() //nolint:gosec // Jitter does not need a secure source.
+	return time.Duration(delay)
+}
+
+// isRetryable reports whether a failed request can be retried.
+// Whether the caller still waits for the result is checked separately on its context,
+// because an attempt cut by the client timeout also reports context.DeadlineExceeded.
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter returns the delay requested by the error response in the Retry-After header, if any.
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header value, either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
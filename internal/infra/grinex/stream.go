@@ -0,0 +1,432 @@
+package grinex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// ErrBookNotReady is returned by BookStream.Depth when the book of a market is not in sync with the exchange:
+// the market is not subscribed, the stream is disconnected or the book is waiting for a resync.
+var ErrBookNotReady = errors.New("order book not ready")
+
+const (
+	snapshotStream  = "ob-snap"
+	incrementStream = "ob-inc"
+
+	// DefaultStreamReadTimeout is how long the stream waits for a message before reconnecting.
+	DefaultStreamReadTimeout = 30 * time.Second
+	// minResyncInterval is the minimum time between two REST snapshots of a market,
+	// so that a broken stream does not turn every increment into a depth request.
+	minResyncInterval = time.Second
+	// resyncTimeout bounds a REST snapshot request, retries and rate limiting included.
+	resyncTimeout = 10 * time.Second
+	// maxPendingUpdates is the number of increments kept while a book waits for its REST snapshot.
+	// Older ones are dropped, which reveals a gap and leads to another resync.
+	maxPendingUpdates = 1000
+)
+
+// BookStream subscribes to the order book updates of the Grinex WebSocket API and maintains
+// an in-memory book per market.
+//
+// The exchange sends a snapshot of every subscribed market followed by numbered increments.
+// An increment that does not follow the last applied one reveals a gap, the book is then
+// replaced by a REST snapshot and the increments it already includes are skipped.
+// The snapshot is requested in the background, so that a slow request does not hold up the stream:
+// the increments received meanwhile are kept and applied on top of it.
+type BookStream struct {
+	url         *url.URL
+	client      *Client
+	markets     []string
+	logger      *zap.Logger
+	readTimeout time.Duration
+	reconnect   RetryPolicy
+
+	// mu guards the books, written by the goroutine running Run and by the resyncs it starts.
+	mu      sync.RWMutex
+	books   map[string]*book
+	resyncs sync.WaitGroup
+}
+
+// book is the order book of a market, with the levels keyed by their normalized price.
+type book struct {
+	asks     map[string]bookLevel
+	bids     map[string]bookLevel
+	sequence int64
+	synced   bool
+	// updatedAt is the time the book was last confirmed by a snapshot or an increment.
+	updatedAt time.Time
+
+	// pending are the increments received while the book waits for a REST snapshot.
+	pending    []BookUpdate
+	resyncing  bool
+	lastResync time.Time
+}
+
+type bookLevel struct {
+	price  decimal.Decimal
+	volume string
+}
+
+// StreamOption configures optional BookStream settings.
+type StreamOption func(*BookStream)
+
+// WithStreamLogger sets the logger used to report the connection state and resyncs.
+func WithStreamLogger(logger *zap.Logger) StreamOption {
+	return func(s *BookStream) {
+		s.logger = logger
+	}
+}
+
+// WithStreamReadTimeout sets how long the stream waits for a message before reconnecting.
+// Zero means no limit.
+func WithStreamReadTimeout(timeout time.Duration) StreamOption {
+	return func(s *BookStream) {
+		s.readTimeout = timeout
+	}
+}
+
+// WithReconnectPolicy sets the delays between reconnection attempts. MaxAttempts is ignored,
+// the stream reconnects until Run is stopped.
+func WithReconnectPolicy(policy RetryPolicy) StreamOption {
+	return func(s *BookStream) {
+		s.reconnect = policy
+	}
+}
+
+// NewBookStream creates a new BookStream of the markets from the WebSocket API at streamURL,
+// e.g. "wss://grinex.io/api/v2/ranger/public". The client is used for the REST snapshots;
+// its headers and TLS settings also apply to the WebSocket connection, its proxy does not.
+func NewBookStream(client *Client, streamURL string, markets []string, opts ...StreamOption) (*BookStream, error) {
+	parsedURL, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+	s := &BookStream{
+		url:         parsedURL,
+		client:      client,
+		markets:     markets,
+		logger:      zap.NewNop(),
+		readTimeout: DefaultStreamReadTimeout,
+		reconnect:   DefaultRetryPolicy(),
+		books:       make(map[string]*book, len(markets)),
+	}
+	for _, market := range markets {
+		s.books[market] = newBook()
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Run keeps the stream connected and the books up to date until the context is canceled.
+// While disconnected, Depth reports ErrBookNotReady for every market.
+func (s *BookStream) Run(ctx context.Context) {
+	defer s.resyncs.Wait()
+	for attempt := 1; ; attempt++ {
+		received, err := s.session(ctx)
+		s.reset()
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			attempt = 1
+		}
+
+		delay := s.reconnect.backoff(attempt)
+		s.logger.Warn("Order book stream disconnected, reconnecting", zap.Duration("delay", delay), zap.Error(err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Depth returns the current order book of a market, asks from the lowest price and bids from the highest.
+// The timestamp is the time the book was last updated, so that the book of a market the stream
+// stopped updating ages even while other markets are active.
+func (s *BookStream) Depth(market string) (*DepthResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.books[market]
+	if !ok || !b.synced {
+		return nil, fmt.Errorf("%w: %s", ErrBookNotReady, market)
+	}
+	return &DepthResponse{
+		Timestamp: b.updatedAt.Unix(),
+		Asks:      sortedOrders(b.asks, 1),
+		Bids:      sortedOrders(b.bids, -1),
+		Sequence:  b.sequence,
+	}, nil
+}
+
+// session connects to the stream, subscribes to the markets and applies the updates until the connection fails.
+// It reports whether any message was received, to tell a broken connection from an unreachable server.
+func (s *BookStream) session(ctx context.Context) (bool, error) {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	streams := make([]string, len(s.markets))
+	for i, market := range s.markets {
+		streams[i] = market + "." + incrementStream
+	}
+	subscribe := map[string]any{"event": "subscribe", "streams": streams}
+	if err = websocket.JSON.Send(conn, subscribe); err != nil {
+		return false, err
+	}
+	s.logger.Info("Order book stream connected", zap.Strings("markets", s.markets))
+
+	for received := false; ; received = true {
+		if s.readTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+		}
+		var message map[string]json.RawMessage
+		if err = websocket.JSON.Receive(conn, &message); err != nil {
+			return received, err
+		}
+		for stream, payload := range message {
+			s.handle(ctx, stream, payload)
+		}
+	}
+}
+
+// dial opens the WebSocket connection with the headers and TLS settings of the REST client.
+func (s *BookStream) dial(ctx context.Context) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig(s.url.String(), s.client.baseURL.String())
+	if err != nil {
+		return nil, err
+	}
+	config.Header = s.client.headers.Clone()
+	if s.client.transport != nil {
+		config.TlsConfig = s.client.transport.TLSClientConfig
+	}
+	if s.readTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.readTimeout)
+		defer cancel()
+	}
+	return config.DialContext(ctx)
+}
+
+// handle applies a message of a market stream, e.g. "usdtrub.ob-inc". Other messages are ignored.
+func (s *BookStream) handle(ctx context.Context, stream string, payload json.RawMessage) {
+	market, kind, ok := strings.Cut(stream, ".")
+	if !ok || s.books[market] == nil || (kind != snapshotStream && kind != incrementStream) {
+		return
+	}
+	logger := s.logger.With(zap.String("market", market), zap.String("stream", kind))
+
+	var update BookUpdate
+	err := json.Unmarshal(payload, &update)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.books[market]
+	switch {
+	case err != nil:
+		// A lost update leaves the book unusable until it is resynced.
+		logger.Warn("Invalid order book update", zap.Error(err))
+		b.invalidate()
+	case kind == snapshotStream:
+		b.replace(update.Asks, update.Bids, update.Sequence)
+	default:
+		s.applyIncrement(ctx, logger, market, b, update)
+	}
+}
+
+// applyIncrement applies an increment to the book of a market. If the book is not in sync or the increment
+// reveals a gap, the increment is kept for the resync instead. It must be called with s.mu held.
+func (s *BookStream) applyIncrement(
+	ctx context.Context,
+	logger *zap.Logger,
+	market string,
+	b *book,
+	update BookUpdate,
+) {
+	if b.synced && update.Sequence > b.sequence+1 {
+		logger.Warn("Order book sequence gap, resyncing",
+			zap.Int64("expected", b.sequence+1), zap.Int64("received", update.Sequence))
+		b.invalidate()
+	}
+	if !b.synced {
+		if len(b.pending) == maxPendingUpdates {
+			b.pending = slices.Delete(b.pending, 0, 1)
+		}
+		b.pending = append(b.pending, update)
+		s.startResync(ctx, logger, market, b)
+		return
+	}
+	b.apply(logger, update)
+}
+
+// startResync requests a REST snapshot of the book in the background, unless one is in flight
+// or the last one was requested less than minResyncInterval ago. It must be called with s.mu held.
+// A snapshot without a sequence number is taken as including the increment that triggered the resync.
+func (s *BookStream) startResync(ctx context.Context, logger *zap.Logger, market string, b *book) {
+	if b.resyncing || time.Since(b.lastResync) < minResyncInterval {
+		return
+	}
+	b.resyncing = true
+	b.lastResync = time.Now()
+	sequence := b.pending[len(b.pending)-1].Sequence
+
+	s.resyncs.Add(1)
+	go func() {
+		defer s.resyncs.Done()
+		ctx, cancel := context.WithTimeout(ctx, resyncTimeout)
+		defer cancel()
+		snapshot, err := s.client.GetDepth(ctx, market)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		b.resyncing = false
+		switch {
+		case s.books[market] != b || b.synced:
+			// The connection was lost or the stream sent a snapshot meanwhile.
+			return
+		case err != nil:
+			logger.Warn("Failed to get the order book snapshot", zap.Error(err))
+			return
+		}
+		if snapshot.Sequence == 0 {
+			snapshot.Sequence = sequence
+		}
+		pending := b.pending
+		b.replace(snapshotLevels(snapshot.Asks), snapshotLevels(snapshot.Bids), snapshot.Sequence)
+		logger.Info("Order book resynced", zap.Int64("sequence", snapshot.Sequence))
+		for _, update := range pending {
+			if !b.synced {
+				break
+			}
+			b.apply(logger, update)
+		}
+	}()
+}
+
+// apply applies an increment to a book in sync, skipping the increments the book already includes
+// and marking the book out of sync on a gap.
+func (b *book) apply(logger *zap.Logger, update BookUpdate) {
+	switch {
+	case update.Sequence <= b.sequence:
+		// Already included in the snapshot.
+		return
+	case update.Sequence > b.sequence+1:
+		// The snapshot is older than the stream, wait for the next resync.
+		logger.Warn("Order book snapshot is behind the stream",
+			zap.Int64("snapshot", b.sequence), zap.Int64("received", update.Sequence))
+		b.invalidate()
+		return
+	}
+	applyLevels(b.asks, update.Asks)
+	applyLevels(b.bids, update.Bids)
+	b.sequence = update.Sequence
+	b.updatedAt = time.Now()
+}
+
+// replace replaces the levels of the book and marks it in sync.
+func (b *book) replace(asks, bids PriceLevels, sequence int64) {
+	clear(b.asks)
+	clear(b.bids)
+	applyLevels(b.asks, asks)
+	applyLevels(b.bids, bids)
+	b.sequence = sequence
+	b.synced = true
+	b.updatedAt = time.Now()
+	b.pending = nil
+}
+
+// invalidate marks the book out of sync.
+func (b *book) invalidate() {
+	b.synced = false
+	b.pending = nil
+}
+
+// reset marks all books out of sync after the connection is lost.
+func (s *BookStream) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for market := range s.books {
+		s.books[market] = newBook()
+	}
+}
+
+// snapshotLevels converts the orders of a REST snapshot to price levels.
+func snapshotLevels(orders []Order) PriceLevels {
+	levels := make(PriceLevels, len(orders))
+	for i, order := range orders {
+		levels[i] = []string{order.Price, order.Volume}
+	}
+	return levels
+}
+
+func newBook() *book {
+	return &book{
+		asks: make(map[string]bookLevel),
+		bids: make(map[string]bookLevel),
+	}
+}
+
+// applyLevels sets the volumes of the price levels, removing the levels with an empty or zero volume.
+// Levels with a price or volume that is not a number are skipped.
+func applyLevels(levels map[string]bookLevel, updates PriceLevels) {
+	for _, update := range updates {
+		if len(update) < 2 {
+			continue
+		}
+		price, err := decimal.NewFromString(update[0])
+		if err != nil {
+			continue
+		}
+		key := price.String()
+		if update[1] == "" {
+			delete(levels, key)
+			continue
+		}
+		volume, err := decimal.NewFromString(update[1])
+		switch {
+		case err != nil:
+			continue
+		case volume.IsZero():
+			delete(levels, key)
+		default:
+			levels[key] = bookLevel{price: price, volume: update[1]}
+		}
+	}
+}
+
+// sortedOrders returns the levels as orders sorted by price, ascending for order 1 and descending for -1.
+func sortedOrders(levels map[string]bookLevel, order int) []Order {
+	sorted := make([]bookLevel, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, level)
+	}
+	slices.SortFunc(sorted, func(a, b bookLevel) int {
+		return order * a.price.Cmp(b.price)
+	})
+
+	orders := make([]Order, len(sorted))
+	for i, level := range sorted {
+		orders[i] = Order{Price: level.price.String(), Volume: level.volume}
+	}
+	return orders
+}
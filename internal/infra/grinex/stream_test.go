@@ -0,0 +1,219 @@
+//nolint:testpackage // This file needs to be in the same package because we are testing the package internals.
+package grinex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// newTestStream runs a BookStream of usdtrub against a local server that answers the subscription
+// with the messages and serves REST snapshots with depth.
+func newTestStream(t *testing.T, messages []string, depth http.HandlerFunc) *BookStream {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle("/ws", websocket.Handler(func(conn *websocket.Conn) {
+		var subscribe struct {
+			Event   string   `json:"event"`
+			Streams []string `json:"streams"`
+		}
+		if !assert.NoError(t, websocket.JSON.Receive(conn, &subscribe)) {
+			return
+		}
+		assert.Equal(t, []string{"usdtrub.ob-inc"}, subscribe.Streams)
+		for _, message := range messages {
+			if !assert.NoError(t, websocket.Message.Send(conn, message)) {
+				return
+			}
+		}
+		// Keep the connection open until the client closes it.
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}))
+	if depth != nil {
+		mux.Handle(getDepthEndpoint, depth)
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	stream, err := NewBookStream(client, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws", []string{"usdtrub"},
+		WithReconnectPolicy(RetryPolicy{InitialBackoff: time.Hour}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return stream
+}
+
+// waitForSequence waits until the book of usdtrub has applied the update with the sequence number.
+func waitForSequence(t *testing.T, stream *BookStream, sequence int64) *DepthResponse {
+	t.Helper()
+	var depth *DepthResponse
+	require.Eventually(t, func() bool {
+		var err error
+		depth, err = stream.Depth("usdtrub")
+		return err == nil && depth.Sequence == sequence
+	}, time.Second, 5*time.Millisecond)
+	return depth
+}
+
+func TestBookStream(t *testing.T) {
+	t.Run("applies increments to the snapshot", func(t *testing.T) {
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-snap":{"asks":[["80.5","10"],["80.70","5"]],"bids":[["80.1","2"]],"sequence":1}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.5","0"],"sequence":2}}`,
+			`{"usdtrub.ob-inc":{"bids":[["80.2","1"],["80.3",""]],"sequence":3}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.6","3"],"sequence":3}}`,
+		}, nil)
+
+		depth := waitForSequence(t, stream, 3)
+		assert.Equal(t, []Order{{Price: "80.7", Volume: "5"}}, depth.Asks)
+		assert.Equal(t, []Order{{Price: "80.2", Volume: "1"}, {Price: "80.1", Volume: "2"}}, depth.Bids)
+		assert.InDelta(t, time.Now().Unix(), depth.Timestamp, 1)
+
+		_, err := stream.Depth("btcusdt")
+		require.ErrorIs(t, err, ErrBookNotReady)
+	})
+
+	t.Run("resyncs from a REST snapshot on gaps", func(t *testing.T) {
+		var snapshots atomic.Int32
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-snap":{"asks":[["80.5","10"]],"bids":[["80.1","2"]],"sequence":1}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.5","4"],"sequence":5}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.5","7"],"sequence":6}}`,
+			`{"usdtrub.ob-inc":{"bids":["80.2","1"],"sequence":7}}`,
+		}, func(w http.ResponseWriter, r *http.Request) {
+			snapshots.Add(1)
+			assert.Equal(t, "usdtrub", r.URL.Query().Get("market"))
+			_ = json.NewEncoder(w).Encode(DepthResponse{
+				Asks:     []Order{{Price: "80.5", Volume: "7"}, {Price: "80.6", Volume: "1"}},
+				Bids:     []Order{{Price: "80.1", Volume: "3"}},
+				Sequence: 6,
+			})
+		})
+
+		depth := waitForSequence(t, stream, 7)
+		assert.Equal(t, []Order{{Price: "80.5", Volume: "7"}, {Price: "80.6", Volume: "1"}}, depth.Asks)
+		assert.Equal(t, []Order{{Price: "80.2", Volume: "1"}, {Price: "80.1", Volume: "3"}}, depth.Bids)
+		assert.Equal(t, int32(1), snapshots.Load())
+	})
+
+	t.Run("increments before a snapshot trigger a resync", func(t *testing.T) {
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-inc":{"asks":["80.5","4"],"sequence":3}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.5","5"],"sequence":4}}`,
+		}, func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(DepthResponse{Asks: []Order{{Price: "80.5", Volume: "1"}}})
+		})
+
+		depth := waitForSequence(t, stream, 4)
+		assert.Equal(t, []Order{{Price: "80.5", Volume: "5"}}, depth.Asks)
+	})
+
+	t.Run("increments are kept while the snapshot is requested", func(t *testing.T) {
+		release := make(chan struct{})
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-inc":{"asks":["80.5","4"],"sequence":3}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.5","5"],"sequence":4}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.6","1"],"sequence":5}}`,
+		}, func(w http.ResponseWriter, _ *http.Request) {
+			<-release
+			_ = json.NewEncoder(w).Encode(DepthResponse{Asks: []Order{{Price: "80.5", Volume: "1"}}, Sequence: 3})
+		})
+
+		// The stream goes on reading while the snapshot request hangs.
+		require.Eventually(t, func() bool {
+			stream.mu.RLock()
+			defer stream.mu.RUnlock()
+			return len(stream.books["usdtrub"].pending) == 3
+		}, time.Second, 5*time.Millisecond)
+		_, err := stream.Depth("usdtrub")
+		require.ErrorIs(t, err, ErrBookNotReady)
+
+		close(release)
+		depth := waitForSequence(t, stream, 5)
+		assert.Equal(t, []Order{{Price: "80.5", Volume: "5"}, {Price: "80.6", Volume: "1"}}, depth.Asks)
+	})
+
+	t.Run("timestamp is the last update of the book", func(t *testing.T) {
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-snap":{"asks":[["80.5","10"]],"bids":[["80.1","2"]],"sequence":1}}`,
+		}, nil)
+		waitForSequence(t, stream, 1)
+
+		updatedAt := time.Now().Add(-time.Minute)
+		stream.mu.Lock()
+		stream.books["usdtrub"].updatedAt = updatedAt
+		stream.mu.Unlock()
+		depth, err := stream.Depth("usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, updatedAt.Unix(), depth.Timestamp)
+	})
+
+	t.Run("invalid update triggers a resync", func(t *testing.T) {
+		var snapshots atomic.Int32
+		stream := newTestStream(t, []string{
+			`{"usdtrub.ob-snap":{"asks":[["80.5","10"]],"bids":[],"sequence":1}}`,
+			`{"usdtrub.ob-inc":{"asks":{"price":"80.5"},"sequence":2}}`,
+			`{"usdtrub.ob-inc":{"asks":["80.6","1"],"sequence":2}}`,
+		}, func(w http.ResponseWriter, _ *http.Request) {
+			snapshots.Add(1)
+			_ = json.NewEncoder(w).Encode(DepthResponse{Asks: []Order{{Price: "80.4", Volume: "2"}}, Sequence: 2})
+		})
+
+		depth := waitForSequence(t, stream, 2)
+		assert.Equal(t, []Order{{Price: "80.4", Volume: "2"}}, depth.Asks)
+		assert.Equal(t, int32(1), snapshots.Load())
+	})
+}
+
+func TestBookStream_Disconnect(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		connections.Add(1)
+		var subscribe json.RawMessage
+		_ = websocket.JSON.Receive(conn, &subscribe)
+		_ = websocket.Message.Send(conn, `{"usdtrub.ob-snap":{"asks":[["80.5","1"]],"bids":[],"sequence":1}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	stream, err := NewBookStream(client, "ws"+strings.TrimPrefix(server.URL, "http"), []string{"usdtrub"},
+		WithReconnectPolicy(RetryPolicy{InitialBackoff: 10 * time.Millisecond}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool { return connections.Load() >= 3 }, time.Second, 5*time.Millisecond,
+		"reconnects after the server closes the connection")
+	cancel()
+	<-done
+	_, err = stream.Depth("usdtrub")
+	require.ErrorIs(t, err, ErrBookNotReady, "the book is dropped with the connection")
+}
@@ -0,0 +1,110 @@
+package grinex
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// errNoCertificates is returned when a CA file holds no PEM certificates.
+var errNoCertificates = errors.New("no certificates found")
+
+// WithTimeout limits the time of a whole request attempt, from dialing to reading the body.
+// Zero means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithDialTimeout limits the time to establish a TCP connection.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+		c.transport.DialContext = dialer.DialContext
+	}
+}
+
+// WithTLSHandshakeTimeout limits the time of the TLS handshake.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.transport.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout limits the time to wait for the response headers once the request is sent.
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.transport.ResponseHeaderTimeout = timeout
+	}
+}
+
+// WithProxy sends the requests through an HTTP, HTTPS or SOCKS5 proxy, e.g. "socks5://localhost:1080".
+// By default the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Client) {
+		c.transport.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithHeaders adds the headers to every request, e.g. an API key. A User-Agent header replaces the default one.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		for name, value := range headers {
+			c.headers.Set(name, value)
+		}
+	}
+}
+
+// WithTLSConfig sets the TLS settings of the connections to the API, see LoadTLSConfig.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.transport.TLSClientConfig = config
+	}
+}
+
+// WithIdleConns tunes the connection pool: the maximum number of idle connections in total and per host,
+// and how long an idle connection is kept open.
+func WithIdleConns(maxIdle, maxIdlePerHost int, idleTimeout time.Duration) Option {
+	return func(c *Client) {
+		c.transport.MaxIdleConns = maxIdle
+		c.transport.MaxIdleConnsPerHost = maxIdlePerHost
+		c.transport.IdleConnTimeout = idleTimeout
+	}
+}
+
+// LoadTLSConfig builds TLS settings trusting the CA certificates in caFile in addition to the system ones
+// and presenting the client certificate in certFile and keyFile. Empty file names are skipped.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s: %w", caFile, errNoCertificates)
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
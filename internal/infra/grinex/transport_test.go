@@ -0,0 +1,114 @@
+//nolint:testpackage // This file needs to be in the same package because we are testing the package internals.
+package grinex
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func depthHandler(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(DepthResponse{Timestamp: 1})
+}
+
+func TestClient_Transport(t *testing.T) {
+	t.Run("sends default and extra headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, DefaultUserAgent, r.UserAgent())
+			assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+			depthHandler(w, r)
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := NewClient(server.URL, WithHeaders(map[string]string{"X-Api-Key": "secret"}))
+		require.NoError(t, err)
+		_, err = client.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+	})
+
+	t.Run("overall timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := NewClient(server.URL, WithTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+		_, err = client.GetDepth(context.Background(), "usdtrub")
+		require.Error(t, err)
+		assert.True(t, isRetryable(err), "a timed out attempt can be retried")
+	})
+
+	t.Run("proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "grinex.example", r.Host)
+			depthHandler(w, r)
+		}))
+		t.Cleanup(proxy.Close)
+		proxyURL, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+
+		client, err := NewClient("http://grinex.example", WithProxy(proxyURL))
+		require.NoError(t, err)
+		_, err = client.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+	})
+
+	t.Run("custom CA", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(depthHandler))
+		// The untrusted attempt below makes the server log a handshake error.
+		server.Config.ErrorLog = log.New(io.Discard, "", 0)
+		server.StartTLS()
+		t.Cleanup(server.Close)
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+		client, err := NewClient(server.URL)
+		require.NoError(t, err)
+		_, err = client.GetDepth(context.Background(), "usdtrub")
+		require.Error(t, err, "the test server certificate is not trusted by default")
+
+		tlsConfig, err := LoadTLSConfig(caFile, "", "")
+		require.NoError(t, err)
+		client, err = NewClient(server.URL, WithTLSConfig(tlsConfig))
+		require.NoError(t, err)
+		_, err = client.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+	})
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	_, err := LoadTLSConfig(notPEM, "", "")
+	require.ErrorIs(t, err, errNoCertificates)
+
+	_, err = LoadTLSConfig(filepath.Join(dir, "missing.pem"), "", "")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = LoadTLSConfig("", filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	require.Error(t, err)
+
+	config, err := LoadTLSConfig("", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, config.RootCAs)
+	assert.Empty(t, config.Certificates)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// candleIntervals are the supported candle intervals in seconds.
+var candleIntervals = map[string]int64{
+	"1m": 60,
+	"5m": 5 * 60,
+	"1h": 60 * 60,
+	"1d": 24 * 60 * 60,
+}
+
+// ParseCandleInterval returns the length in seconds of a candle interval: "1m", "5m", "1h" or "1d".
+// It returns an error wrapping ErrInvalidHistoryQuery for other intervals.
+func ParseCandleInterval(interval string) (int64, error) {
+	seconds, ok := candleIntervals[interval]
+	if !ok {
+		names := make([]string, 0, len(candleIntervals))
+		for name := range candleIntervals {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return 0, fmt.Errorf("%w: interval %q is not one of %s",
+			ErrInvalidHistoryQuery, interval, strings.Join(names, ", "))
+	}
+	return seconds, nil
+}
+
+// OHLC are the open, high, low and close prices of a candle.
+type OHLC struct {
+	Open  Decimal `json:"open"`
+	High  Decimal `json:"high"`
+	Low   Decimal `json:"low"`
+	Close Decimal `json:"close"`
+}
+
+// Candle aggregates the rates stored for a market during one interval.
+type Candle struct {
+	// Timestamp is the start of the interval in Unix seconds. Intervals are aligned to the Unix epoch, in UTC.
+	Timestamp int64 `json:"timestamp"`
+	Ask       OHLC  `json:"ask"`
+	Bid       OHLC  `json:"bid"`
+	Mid       OHLC  `json:"mid"`
+	// Count is the number of rates in the interval, zero for an empty interval filled with the previous close.
+	Count int64 `json:"count"`
+}
+
+// CandleQuery selects the candles of the rates stored for a market.
+type CandleQuery struct {
+	Market string
+	// Method is the calculation method of the aggregated rates.
+	Method CalculationMethod
+	// Interval is the length of a candle in seconds.
+	Interval int64
+	// From and To bound the timestamps of the rates, From inclusive and To exclusive.
+	From int64
+	To   int64
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// DecimalScale is the number of fractional digits kept in calculated values.
+// It matches the DECIMAL(20,8) columns of the rates table.
+const DecimalScale = 8
+
+// Decimal is an exact decimal number used for prices and volumes.
+//
+// Parsed values are kept exactly as received. Addition, subtraction and multiplication are exact,
+// while division and Round round the result to DecimalScale fractional digits, half away from zero,
+// which is the same rounding PostgreSQL applies when storing a value into a DECIMAL(20,8) column.
+// The zero value is zero.
+type Decimal struct {
+	value decimal.Decimal
+}
+
+// ParseDecimal parses a decimal number such as "102.35".
+// It returns an error wrapping ErrInvalidDecimal if the string is not a finite decimal number.
+func ParseDecimal(s string) (Decimal, error) {
+	value, err := decimal.NewFromString(s)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	return Decimal{value: value}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the string cannot be parsed.
+// It is intended for constants and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDecimalFromInt returns a Decimal equal to the integer.
+func NewDecimalFromInt(i int64) Decimal {
+	return Decimal{value: decimal.NewFromInt(i)}
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{value: d.value.Add(other.value)}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{value: d.value.Sub(other.value)}
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{value: d.value.Mul(other.value)}
+}
+
+// Div returns d / other rounded to DecimalScale fractional digits.
+// It panics if other is zero.
+func (d Decimal) Div(other Decimal) Decimal {
+	return Decimal{value: d.value.DivRound(other.value, DecimalScale)}
+}
+
+// Round returns d rounded to DecimalScale fractional digits.
+func (d Decimal) Round() Decimal {
+	return Decimal{value: d.value.Round(DecimalScale)}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: d.value.Abs()}
+}
+
+// Cmp returns -1 if d < other, 0 if d == other and 1 if d > other.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.value.Cmp(other.value)
+}
+
+// Equal reports whether d and other are numerically equal.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.value.Equal(other.value)
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and 1 if d > 0.
+func (d Decimal) Sign() int {
+	return d.value.Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.value.IsZero()
+}
+
+// IsPositive reports whether d is greater than zero.
+func (d Decimal) IsPositive() bool {
+	return d.value.IsPositive()
+}
+
+// Min returns the smaller of d and other.
+func (d Decimal) Min(other Decimal) Decimal {
+	if d.Cmp(other) <= 0 {
+		return d
+	}
+	return other
+}
+
+// String returns the decimal without trailing zeros, e.g. "102.5".
+func (d Decimal) String() string {
+	return d.value.String()
+}
+
+// MarshalJSON encodes the decimal as a JSON string to avoid float precision loss.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes the decimal from a JSON string or number.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer so that the decimal is stored as an exact numeric value.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for numeric columns.
+func (d *Decimal) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*d = NewDecimalFromInt(v)
+		return nil
+	case float64:
+		*d = Decimal{value: decimal.NewFromFloat(v)}
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidDecimal, src)
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,63 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "integer", input: "100", want: "100"},
+		{name: "fraction", input: "102.350", want: "102.35"},
+		{name: "more digits than the scale are kept", input: "0.123456789", want: "0.123456789"},
+		{name: "not a number", input: "abc", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "nan", input: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseDecimal(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, models.ErrInvalidDecimal)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestDecimal_Rounding(t *testing.T) {
+	one := models.MustParseDecimal("1")
+	three := models.MustParseDecimal("3")
+	two := models.MustParseDecimal("2")
+
+	assert.Equal(t, "0.33333333", one.Div(three).String())
+	assert.Equal(t, "0.66666667", two.Div(three).String())
+	assert.Equal(t, "0.00000001", models.MustParseDecimal("0.000000005").Round().String())
+	assert.Equal(t, "-0.00000001", models.MustParseDecimal("-0.000000005").Round().String())
+	assert.Equal(t, "1.23456789", models.MustParseDecimal("1.234567891").Round().String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	data, err := json.Marshal(models.MustParseDecimal("102.5"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"102.5"`, string(data))
+
+	var fromString, fromNumber models.Decimal
+	require.NoError(t, json.Unmarshal([]byte(`"102.5"`), &fromString))
+	require.NoError(t, json.Unmarshal([]byte(`102.5`), &fromNumber))
+	assert.True(t, fromString.Equal(fromNumber))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`"abc"`), &fromString), models.ErrInvalidDecimal)
+}
@@ -1,10 +1,62 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
+	// ErrInvalidDepth indicates that the depth data received from the exchange is invalid.
+	// All depth validation errors below wrap it.
+	ErrInvalidDepth = errors.New("invalid depth data")
 	// ErrIncompleteDepthData indicates that the depth data (asks or bids) is incomplete.
-	ErrIncompleteDepthData = errors.New("incomplete depth data")
+	ErrIncompleteDepthData = fmt.Errorf("%w: incomplete depth data", ErrInvalidDepth)
 	// ErrInvalidTimestamp indicates that the timestamp is invalid.
-	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrInvalidTimestamp = fmt.Errorf("%w: invalid timestamp", ErrInvalidDepth)
+	// ErrUnsortedAsks indicates that the asks are not sorted by price in ascending order.
+	ErrUnsortedAsks = fmt.Errorf("%w: asks are not sorted ascending", ErrInvalidDepth)
+	// ErrUnsortedBids indicates that the bids are not sorted by price in descending order.
+	ErrUnsortedBids = fmt.Errorf("%w: bids are not sorted descending", ErrInvalidDepth)
+	// ErrNonPositivePrice indicates that an order has a zero or negative price.
+	ErrNonPositivePrice = fmt.Errorf("%w: non-positive price", ErrInvalidDepth)
+	// ErrNonPositiveVolume indicates that an order has a zero or negative volume.
+	ErrNonPositiveVolume = fmt.Errorf("%w: non-positive volume", ErrInvalidDepth)
+	// ErrAmountMismatch indicates that an order amount does not match its price multiplied by its volume.
+	ErrAmountMismatch = fmt.Errorf("%w: amount does not match price * volume", ErrInvalidDepth)
+	// ErrCrossedBook indicates that the bid price is greater than or equal to the ask price.
+	ErrCrossedBook = fmt.Errorf("%w: crossed or locked book", ErrInvalidDepth)
+	// ErrUnknownMarket indicates that the depth provider does not know the requested market.
+	ErrUnknownMarket = errors.New("unknown market")
+	// ErrUnsupportedMarket indicates that the requested market is not in the list of markets of the exchange.
+	ErrUnsupportedMarket = errors.New("unsupported market")
+	// ErrNotConfigured indicates that the requested feature is not configured in the service.
+	ErrNotConfigured = errors.New("not configured")
+	// ErrInvalidTrade indicates that the trade data received from the exchange is invalid.
+	ErrInvalidTrade = errors.New("invalid trade data")
+	// ErrNoTrades indicates that the exchange returned no trades for the market.
+	ErrNoTrades = errors.New("no trades")
+	// ErrUpstreamUnavailable indicates that the exchange is down, throttling us or refusing the request.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrUpstreamTimeout indicates that the exchange did not answer in time.
+	ErrUpstreamTimeout = errors.New("upstream timeout")
+	// ErrAllProvidersFailed indicates that none of the depth providers returned valid depth data.
+	ErrAllProvidersFailed = errors.New("all depth providers failed")
+	// ErrNoConsensus indicates that too few venues agree on the price to build a consensus rate.
+	ErrNoConsensus = errors.New("no consensus between venues")
+	// ErrCircuitOpen indicates that a depth provider is not called because its circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+	// ErrRateLimited indicates that a depth provider was not called to stay within its request budget.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrStaleDepth indicates that the depth data is older than the maximum allowed age.
+	ErrStaleDepth = errors.New("stale depth data")
+	// ErrInvalidDecimal indicates that a value is not a valid decimal number.
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrInvalidCalculationParams indicates that the calculation method or its parameters are invalid.
+	ErrInvalidCalculationParams = errors.New("invalid calculation parameters")
+	// ErrInvalidHistoryQuery indicates that the time range, the limit or the cursor of a rate history query is invalid.
+	ErrInvalidHistoryQuery = errors.New("invalid history query")
+	// ErrRateNotFound indicates that no rate of the market is stored within the lookback window of a point in time.
+	ErrRateNotFound = errors.New("rate not found")
+	// ErrDepthLevelOutOfRange indicates that the depth data has fewer levels than requested.
+	ErrDepthLevelOutOfRange = errors.New("depth level out of range")
 )
@@ -1,11 +1,46 @@
 package models
 
+import "time"
+
+// CalculationMethod is the method used to calculate a rate from the depth data.
+type CalculationMethod string
+
+const (
+	// MethodTop uses the best ask and bid prices.
+	MethodTop CalculationMethod = "top"
+	// MethodTopN uses the prices at level N of the book.
+	MethodTopN CalculationMethod = "topN"
+	// MethodAvgNM uses the average prices of levels N..M of the book.
+	MethodAvgNM CalculationMethod = "avgNM"
+	// MethodVWAP uses the volume-weighted average prices for a target volume.
+	MethodVWAP CalculationMethod = "vwap"
+)
+
 // Rate represents a rate for a specific market.
 type Rate struct {
-	Market    string `json:"market"`
-	AskPrice  string `json:"ask"`
-	BidPrice  string `json:"bid"`
-	Timestamp int64  `json:"timestamp"`
+	Market    string  `json:"market"`
+	AskPrice  Decimal `json:"ask"`
+	BidPrice  Decimal `json:"bid"`
+	Timestamp int64   `json:"timestamp"`
+	// AskPartial and BidPartial report that the side of the book
+	// did not hold enough volume to fill the requested target volume.
+	AskPartial bool `json:"askPartial"`
+	BidPartial bool `json:"bidPartial"`
+	// Method is the calculation method used to get the prices.
+	Method CalculationMethod `json:"method"`
+	// MidPrice is the average of the ask and bid prices.
+	MidPrice Decimal `json:"mid"`
+	// Spread is the difference between the ask and bid prices.
+	Spread Decimal `json:"spread"`
+	// SpreadBps is the spread relative to the mid price in basis points.
+	SpreadBps Decimal `json:"spreadBps"`
+	// Source is the name of the provider the depth data came from.
+	Source string `json:"source"`
+	// Venues lists the venues that contributed to a consensus rate.
+	Venues []string `json:"venues"`
+	// Cached reports that the rate was calculated from cached depth data, CacheAge is the age of that data.
+	Cached   bool          `json:"cached"`
+	CacheAge time.Duration `json:"cacheAge"`
 }
 
 // Depth represents the depth data for a specific market.
@@ -13,13 +48,35 @@ type Depth struct {
 	Timestamp int64   `json:"timestamp"`
 	Asks      []Order `json:"asks"`
 	Bids      []Order `json:"bids"`
+	// Source is the name of the provider that returned the depth data.
+	Source string `json:"source"`
+	// Venues lists the venues that contributed to depth data aggregated from several providers.
+	Venues []string `json:"venues"`
+	// Cached reports that the depth data was not fetched for this request but served from a cache,
+	// CacheAge is the time since it was fetched.
+	Cached   bool          `json:"cached"`
+	CacheAge time.Duration `json:"cacheAge"`
+	// FetchedAt is the time a cache fetched the depth data, shared by every request it served the data to,
+	// so that it identifies the fetch. It is zero for depth data that did not go through a cache.
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Market is a market that rates can be requested for.
+type Market struct {
+	// ID is the market identifier used in requests, e.g. "usdtrub".
+	ID string `json:"id"`
+	// Name is the display name, e.g. "USDT/RUB".
+	Name string `json:"name"`
+	// Base and Quote are the currencies of the market, e.g. "usdt" and "rub".
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
 }
 
 // Order represents an order in the depth data.
 type Order struct {
-	Price  string `json:"price"`
-	Volume string `json:"volume"`
-	Amount string `json:"amount"`
-	Factor string `json:"factor"`
-	Type   string `json:"type"`
+	Price  Decimal `json:"price"`
+	Volume Decimal `json:"volume"`
+	Amount Decimal `json:"amount"`
+	Factor string  `json:"factor"`
+	Type   string  `json:"type"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// CircuitClosed is the state of a circuit that lets requests through.
+const CircuitClosed = "closed"
+
+// CircuitState is the state of the circuit breaker of a depth provider for one market.
+type CircuitState struct {
+	Provider string `json:"provider"`
+	Market   string `json:"market"`
+	// State is "closed", "open" or "half-open".
+	State string `json:"state"`
+	// Since is the time of the last state transition.
+	Since time.Time `json:"since"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StoredRate is a rate read back from the rates table.
+type StoredRate struct {
+	// ID is the identifier of the row. It orders the rates saved with the same timestamp.
+	ID int64 `json:"id"`
+	Rate
+}
+
+// RateCursor is the position of a stored rate in the rate history of a market,
+// which is ordered from the newest rate by timestamp and then by ID.
+type RateCursor struct {
+	Timestamp int64
+	ID        int64
+}
+
+// String encodes the cursor as an opaque token for the callers of the service.
+func (c RateCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(c.Timestamp, 10) + ":" + strconv.FormatInt(c.ID, 10)))
+}
+
+// ParseRateCursor decodes a token returned by RateCursor.String.
+// It returns an error wrapping ErrInvalidHistoryQuery if the token is malformed.
+func ParseRateCursor(token string) (RateCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return RateCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryQuery)
+	}
+	timestamp, id, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return RateCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryQuery)
+	}
+	var cursor RateCursor
+	if cursor.Timestamp, err = strconv.ParseInt(timestamp, 10, 64); err != nil {
+		return RateCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryQuery)
+	}
+	if cursor.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return RateCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryQuery)
+	}
+	return cursor, nil
+}
+
+// RateHistoryQuery selects a page of the stored rates of a market, newest first.
+type RateHistoryQuery struct {
+	Market string
+	// From and To bound the timestamps of the rates, From inclusive and To exclusive. Zero leaves a side open.
+	From int64
+	To   int64
+	// Limit is the maximum number of rates returned.
+	Limit int
+	// After continues the history after the rate at the cursor, nil starts from the newest rate.
+	After *RateCursor
+}
+
+// RateHistory is a page of the stored rates of a market, newest first.
+type RateHistory struct {
+	Market string       `json:"market"`
+	Rates  []StoredRate `json:"rates"`
+	// NextCursor is the token to pass to get the next page, empty on the last page.
+	NextCursor string `json:"nextCursor"`
+}
+
+// LatestRate is the most recent rate of a market, either read from the stored rates or fetched live.
+type LatestRate struct {
+	Rate
+	// Stored reports that the rate was read from the stored rates rather than fetched from the exchange.
+	Stored bool `json:"stored"`
+	// Age is the time since the rate timestamp.
+	Age time.Duration `json:"age"`
+}
@@ -0,0 +1,21 @@
+package models_test
+
+import (
+	"testing"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateCursor(t *testing.T) {
+	cursor := models.RateCursor{Timestamp: 1737901234, ID: 42}
+	parsed, err := models.ParseRateCursor(cursor.String())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	for _, token := range []string{"", "!!!", "MTIz", "YTpi"} {
+		_, err = models.ParseRateCursor(token)
+		require.ErrorIs(t, err, models.ErrInvalidHistoryQuery, token)
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+// Trade is an executed trade of a market.
+type Trade struct {
+	// ID is the identifier of the trade at the exchange.
+	ID     int64   `json:"id"`
+	Market string  `json:"market"`
+	Price  Decimal `json:"price"`
+	Volume Decimal `json:"volume"`
+	// Amount is the traded amount in the quote currency, price * volume.
+	Amount Decimal `json:"amount"`
+	// Side is the side of the taker, "buy" or "sell", empty if unknown.
+	Side string `json:"side"`
+	// Timestamp is the time of the trade in Unix seconds.
+	Timestamp int64 `json:"timestamp"`
+	// Source is the name of the provider the trade came from.
+	Source string `json:"source"`
+}
+
+// RecentTrades are the most recent trades of a market, newest first, and the price of the last one.
+type RecentTrades struct {
+	Market        string  `json:"market"`
+	Trades        []Trade `json:"trades"`
+	LastPrice     Decimal `json:"lastPrice"`
+	LastTimestamp int64   `json:"lastTimestamp"`
+}
@@ -1,6 +1,13 @@
 package models
 
-// Validate checks if the Rate model has valid data.
+import "fmt"
+
+// amountToleranceDivisor sets the relative difference allowed between an order amount
+// and price * volume to 1/10000. The exchange rounds amounts, so they are not expected to match exactly.
+const amountToleranceDivisor = 10000
+
+// Validate checks if the Depth model has valid data.
+// Every error it returns wraps ErrInvalidDepth and one of the more specific errors.
 func (d *Depth) Validate() error {
 	// Check if the depth data has both asks and bids
 	// and if the timestamp is valid.
@@ -11,5 +18,63 @@ func (d *Depth) Validate() error {
 		return ErrInvalidTimestamp
 	}
 
+	// Check every order and the ordering of the levels on both sides.
+	if err := validateSide("asks", d.Asks, 1, ErrUnsortedAsks); err != nil {
+		return err
+	}
+	if err := validateSide("bids", d.Bids, -1, ErrUnsortedBids); err != nil {
+		return err
+	}
+
+	// The best bid must be strictly below the best ask.
+	if d.Bids[0].Price.Cmp(d.Asks[0].Price) >= 0 {
+		return fmt.Errorf("%w: best bid %s, best ask %s", ErrCrossedBook, d.Bids[0].Price, d.Asks[0].Price)
+	}
+
+	return nil
+}
+
+// Validate checks that the order has a positive price and volume
+// and that its amount matches price * volume.
+func (o *Order) Validate() error {
+	if !o.Price.IsPositive() {
+		return fmt.Errorf("%w: %s", ErrNonPositivePrice, o.Price)
+	}
+	if !o.Volume.IsPositive() {
+		return fmt.Errorf("%w: %s", ErrNonPositiveVolume, o.Volume)
+	}
+	expected := o.Price.Mul(o.Volume)
+	diff := o.Amount.Sub(expected).Abs()
+	if diff.Mul(NewDecimalFromInt(amountToleranceDivisor)).Cmp(expected) > 0 {
+		return fmt.Errorf("%w: amount %s, price * volume %s", ErrAmountMismatch, o.Amount, expected)
+	}
+	return nil
+}
+
+// validateSide validates the orders of one side of the book and checks that their prices
+// are sorted in the given direction: 1 for ascending and -1 for descending.
+func validateSide(side string, orders []Order, direction int, errUnsorted error) error {
+	for i := range orders {
+		if err := orders[i].Validate(); err != nil {
+			return fmt.Errorf("%s[%d]: %w", side, i, err)
+		}
+		if i > 0 && orders[i].Price.Cmp(orders[i-1].Price)*direction < 0 {
+			return fmt.Errorf("%w: %s[%d] %s after %s", errUnsorted, side, i, orders[i].Price, orders[i-1].Price)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the trade has a positive price, volume and timestamp.
+// All returned errors wrap ErrInvalidTrade.
+func (t Trade) Validate() error {
+	switch {
+	case !t.Price.IsPositive():
+		return fmt.Errorf("%w: trade %d has non-positive price %s", ErrInvalidTrade, t.ID, t.Price)
+	case !t.Volume.IsPositive():
+		return fmt.Errorf("%w: trade %d has non-positive volume %s", ErrInvalidTrade, t.ID, t.Volume)
+	case t.Timestamp <= 0:
+		return fmt.Errorf("%w: trade %d has invalid timestamp %d", ErrInvalidTrade, t.ID, t.Timestamp)
+	}
 	return nil
 }
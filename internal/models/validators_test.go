@@ -0,0 +1,102 @@
+package models_test
+
+import (
+	"testing"
+	"usdt-rate-service/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func order(price, volume, amount string) models.Order {
+	return models.Order{
+		Price:  models.MustParseDecimal(price),
+		Volume: models.MustParseDecimal(volume),
+		Amount: models.MustParseDecimal(amount),
+	}
+}
+
+func TestDepth_Validate(t *testing.T) {
+	validAsks := []models.Order{order("100", "2", "200"), order("101", "1", "101")}
+	validBids := []models.Order{order("99", "1", "99"), order("98", "3", "294")}
+
+	tests := []struct {
+		name    string
+		asks    []models.Order
+		bids    []models.Order
+		ts      int64
+		wantErr error
+	}{
+		{name: "valid", asks: validAsks, bids: validBids, ts: 1},
+		{name: "no asks", bids: validBids, ts: 1, wantErr: models.ErrIncompleteDepthData},
+		{name: "no bids", asks: validAsks, ts: 1, wantErr: models.ErrIncompleteDepthData},
+		{name: "zero timestamp", asks: validAsks, bids: validBids, wantErr: models.ErrInvalidTimestamp},
+		{
+			name:    "unsorted asks",
+			asks:    []models.Order{order("101", "1", "101"), order("100", "2", "200")},
+			bids:    validBids,
+			ts:      1,
+			wantErr: models.ErrUnsortedAsks,
+		},
+		{
+			name:    "unsorted bids",
+			asks:    validAsks,
+			bids:    []models.Order{order("98", "3", "294"), order("99", "1", "99")},
+			ts:      1,
+			wantErr: models.ErrUnsortedBids,
+		},
+		{
+			name:    "zero price",
+			asks:    []models.Order{order("0", "1", "0")},
+			bids:    validBids,
+			ts:      1,
+			wantErr: models.ErrNonPositivePrice,
+		},
+		{
+			name:    "negative volume",
+			asks:    validAsks,
+			bids:    []models.Order{order("99", "-1", "-99")},
+			ts:      1,
+			wantErr: models.ErrNonPositiveVolume,
+		},
+		{
+			name:    "amount mismatch",
+			asks:    []models.Order{order("100", "2", "250")},
+			bids:    validBids,
+			ts:      1,
+			wantErr: models.ErrAmountMismatch,
+		},
+		{
+			name: "amount within rounding tolerance",
+			asks: []models.Order{order("100.123", "2.5", "250.31")},
+			bids: validBids,
+			ts:   1,
+		},
+		{
+			name:    "crossed book",
+			asks:    validAsks,
+			bids:    []models.Order{order("100.5", "1", "100.5")},
+			ts:      1,
+			wantErr: models.ErrCrossedBook,
+		},
+		{
+			name:    "locked book",
+			asks:    validAsks,
+			bids:    []models.Order{order("100", "1", "100")},
+			ts:      1,
+			wantErr: models.ErrCrossedBook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth := &models.Depth{Timestamp: tt.ts, Asks: tt.asks, Bids: tt.bids}
+			err := depth.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			require.ErrorIs(t, err, models.ErrInvalidDepth)
+		})
+	}
+}
@@ -21,9 +21,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CalculationMethod int32
+
+const (
+	CalculationMethod_CALCULATION_METHOD_UNSPECIFIED CalculationMethod = 0 // vwap if targetVolume is set, top otherwise
+	CalculationMethod_CALCULATION_METHOD_TOP         CalculationMethod = 1 // best ask and bid prices
+	CalculationMethod_CALCULATION_METHOD_TOP_N       CalculationMethod = 2 // prices at level n
+	CalculationMethod_CALCULATION_METHOD_AVG_NM      CalculationMethod = 3 // average prices of levels n..m
+	CalculationMethod_CALCULATION_METHOD_VWAP        CalculationMethod = 4 // volume-weighted prices for targetVolume
+)
+
+// Enum value maps for CalculationMethod.
+var (
+	CalculationMethod_name = map[int32]string{
+		0: "CALCULATION_METHOD_UNSPECIFIED",
+		1: "CALCULATION_METHOD_TOP",
+		2: "CALCULATION_METHOD_TOP_N",
+		3: "CALCULATION_METHOD_AVG_NM",
+		4: "CALCULATION_METHOD_VWAP",
+	}
+	CalculationMethod_value = map[string]int32{
+		"CALCULATION_METHOD_UNSPECIFIED": 0,
+		"CALCULATION_METHOD_TOP":         1,
+		"CALCULATION_METHOD_TOP_N":       2,
+		"CALCULATION_METHOD_AVG_NM":      3,
+		"CALCULATION_METHOD_VWAP":        4,
+	}
+)
+
+func (x CalculationMethod) Enum() *CalculationMethod {
+	p := new(CalculationMethod)
+	*p = x
+	return p
+}
+
+func (x CalculationMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CalculationMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_rates_proto_enumTypes[0].Descriptor()
+}
+
+func (CalculationMethod) Type() protoreflect.EnumType {
+	return &file_rates_proto_enumTypes[0]
+}
+
+func (x CalculationMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CalculationMethod.Descriptor instead.
+func (CalculationMethod) EnumDescriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{0}
+}
+
 type GetRatesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	TargetVolume  string                 `protobuf:"bytes,2,opt,name=targetVolume,proto3" json:"targetVolume,omitempty"` // volume to fill on each side, empty means top of the book
+	Method        CalculationMethod      `protobuf:"varint,3,opt,name=method,proto3,enum=rates.CalculationMethod" json:"method,omitempty"`
+	N             uint32                 `protobuf:"varint,4,opt,name=n,proto3" json:"n,omitempty"` // 1-based book level for topN and first level for avgNM
+	M             uint32                 `protobuf:"varint,5,opt,name=m,proto3" json:"m,omitempty"` // 1-based last book level for avgNM
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,11 +124,49 @@ func (x *GetRatesRequest) GetMarket() string {
 	return ""
 }
 
+func (x *GetRatesRequest) GetTargetVolume() string {
+	if x != nil {
+		return x.TargetVolume
+	}
+	return ""
+}
+
+func (x *GetRatesRequest) GetMethod() CalculationMethod {
+	if x != nil {
+		return x.Method
+	}
+	return CalculationMethod_CALCULATION_METHOD_UNSPECIFIED
+}
+
+func (x *GetRatesRequest) GetN() uint32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *GetRatesRequest) GetM() uint32 {
+	if x != nil {
+		return x.M
+	}
+	return 0
+}
+
 type Rate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AskPrice      string                 `protobuf:"bytes,1,opt,name=askPrice,proto3" json:"askPrice,omitempty"`
 	BidPrice      string                 `protobuf:"bytes,2,opt,name=bidPrice,proto3" json:"bidPrice,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	AskPartial    bool                   `protobuf:"varint,4,opt,name=askPartial,proto3" json:"askPartial,omitempty"` // asks could not fill the whole target volume
+	BidPartial    bool                   `protobuf:"varint,5,opt,name=bidPartial,proto3" json:"bidPartial,omitempty"` // bids could not fill the whole target volume
+	Method        CalculationMethod      `protobuf:"varint,6,opt,name=method,proto3,enum=rates.CalculationMethod" json:"method,omitempty"`
+	MidPrice      string                 `protobuf:"bytes,7,opt,name=midPrice,proto3" json:"midPrice,omitempty"`
+	Spread        string                 `protobuf:"bytes,8,opt,name=spread,proto3" json:"spread,omitempty"`           // askPrice - bidPrice
+	SpreadBps     string                 `protobuf:"bytes,9,opt,name=spreadBps,proto3" json:"spreadBps,omitempty"`     // spread relative to midPrice in basis points
+	Source        string                 `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`          // depth provider the rate was calculated from
+	Venues        []string               `protobuf:"bytes,11,rep,name=venues,proto3" json:"venues,omitempty"`          // venues that contributed to a consensus rate
+	Cached        bool                   `protobuf:"varint,12,opt,name=cached,proto3" json:"cached,omitempty"`         // calculated from cached depth data
+	CacheAgeMs    int64                  `protobuf:"varint,13,opt,name=cacheAgeMs,proto3" json:"cacheAgeMs,omitempty"` // age of the cached depth data in milliseconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,6 +222,76 @@ func (x *Rate) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *Rate) GetAskPartial() bool {
+	if x != nil {
+		return x.AskPartial
+	}
+	return false
+}
+
+func (x *Rate) GetBidPartial() bool {
+	if x != nil {
+		return x.BidPartial
+	}
+	return false
+}
+
+func (x *Rate) GetMethod() CalculationMethod {
+	if x != nil {
+		return x.Method
+	}
+	return CalculationMethod_CALCULATION_METHOD_UNSPECIFIED
+}
+
+func (x *Rate) GetMidPrice() string {
+	if x != nil {
+		return x.MidPrice
+	}
+	return ""
+}
+
+func (x *Rate) GetSpread() string {
+	if x != nil {
+		return x.Spread
+	}
+	return ""
+}
+
+func (x *Rate) GetSpreadBps() string {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return ""
+}
+
+func (x *Rate) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Rate) GetVenues() []string {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+func (x *Rate) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *Rate) GetCacheAgeMs() int64 {
+	if x != nil {
+		return x.CacheAgeMs
+	}
+	return 0
+}
+
 type GetRatesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Rate          *Rate                  `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
@@ -169,26 +336,30 @@ func (x *GetRatesResponse) GetRate() *Rate {
 	return nil
 }
 
-type HealthCheckRequest struct {
+type Market struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`       // market identifier used in requests, e.g. "usdtrub"
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`   // display name, e.g. "USDT/RUB"
+	Base          string                 `protobuf:"bytes,3,opt,name=base,proto3" json:"base,omitempty"`   // base currency, e.g. "usdt"
+	Quote         string                 `protobuf:"bytes,4,opt,name=quote,proto3" json:"quote,omitempty"` // quote currency, e.g. "rub"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
+func (x *Market) Reset() {
+	*x = Market{}
 	mi := &file_rates_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckRequest) String() string {
+func (x *Market) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
+func (*Market) ProtoMessage() {}
 
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+func (x *Market) ProtoReflect() protoreflect.Message {
 	mi := &file_rates_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -200,32 +371,59 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Market.ProtoReflect.Descriptor instead.
+func (*Market) Descriptor() ([]byte, []int) {
 	return file_rates_proto_rawDescGZIP(), []int{3}
 }
 
-type HealthCheckResponse struct {
+func (x *Market) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Market) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Market) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *Market) GetQuote() string {
+	if x != nil {
+		return x.Quote
+	}
+	return ""
+}
+
+type ListMarketsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
+func (x *ListMarketsRequest) Reset() {
+	*x = ListMarketsRequest{}
 	mi := &file_rates_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *ListMarketsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*ListMarketsRequest) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+func (x *ListMarketsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rates_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -237,36 +435,1156 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
-func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListMarketsRequest.ProtoReflect.Descriptor instead.
+func (*ListMarketsRequest) Descriptor() ([]byte, []int) {
 	return file_rates_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *HealthCheckResponse) GetStatus() string {
+type ListMarketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Markets       []*Market              `protobuf:"bytes,1,rep,name=markets,proto3" json:"markets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMarketsResponse) Reset() {
+	*x = ListMarketsResponse{}
+	mi := &file_rates_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMarketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMarketsResponse) ProtoMessage() {}
+
+func (x *ListMarketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[5]
 	if x != nil {
-		return x.Status
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMarketsResponse.ProtoReflect.Descriptor instead.
+func (*ListMarketsResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListMarketsResponse) GetMarkets() []*Market {
+	if x != nil {
+		return x.Markets
+	}
+	return nil
+}
+
+type GetTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Limit         uint32                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // number of most recent trades, 0 means the default of 50, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradesRequest) Reset() {
+	*x = GetTradesRequest{}
+	mi := &file_rates_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradesRequest) ProtoMessage() {}
+
+func (x *GetTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradesRequest.ProtoReflect.Descriptor instead.
+func (*GetTradesRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetTradesRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
 	}
 	return ""
 }
 
-var File_rates_proto protoreflect.FileDescriptor
+func (x *GetTradesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-const file_rates_proto_rawDesc = "" +
-	"\n" +
-	"\vrates.proto\x12\x05rates\")\n" +
-	"\x0fGetRatesRequest\x12\x16\n" +
-	"\x06market\x18\x01 \x01(\tR\x06market\"\\\n" +
-	"\x04Rate\x12\x1a\n" +
-	"\baskPrice\x18\x01 \x01(\tR\baskPrice\x12\x1a\n" +
-	"\bbidPrice\x18\x02 \x01(\tR\bbidPrice\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"3\n" +
-	"\x10GetRatesResponse\x12\x1f\n" +
-	"\x04rate\x18\x01 \x01(\v2\v.rates.RateR\x04rate\"\x14\n" +
-	"\x12HealthCheckRequest\"-\n" +
-	"\x13HealthCheckResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2\x91\x01\n" +
-	"\fRatesService\x12;\n" +
-	"\bGetRates\x12\x16.rates.GetRatesRequest\x1a\x17.rates.GetRatesResponse\x12D\n" +
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"` // trade identifier on the exchange
+	Price         string                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Volume        string                 `protobuf:"bytes,3,opt,name=volume,proto3" json:"volume,omitempty"` // traded base currency volume
+	Amount        string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"` // traded quote currency amount
+	Side          string                 `protobuf:"bytes,5,opt,name=side,proto3" json:"side,omitempty"`     // taker side: buy or sell
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_rates_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Trade) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Trade) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Trade) GetVolume() string {
+	if x != nil {
+		return x.Volume
+	}
+	return ""
+}
+
+func (x *Trade) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Trade) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Trade) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type GetTradesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trades        []*Trade               `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`                // newest first
+	LastPrice     string                 `protobuf:"bytes,2,opt,name=lastPrice,proto3" json:"lastPrice,omitempty"`          // price of the most recent trade
+	LastTimestamp int64                  `protobuf:"varint,3,opt,name=lastTimestamp,proto3" json:"lastTimestamp,omitempty"` // time of the most recent trade
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradesResponse) Reset() {
+	*x = GetTradesResponse{}
+	mi := &file_rates_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradesResponse) ProtoMessage() {}
+
+func (x *GetTradesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradesResponse.ProtoReflect.Descriptor instead.
+func (*GetTradesResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTradesResponse) GetTrades() []*Trade {
+	if x != nil {
+		return x.Trades
+	}
+	return nil
+}
+
+func (x *GetTradesResponse) GetLastPrice() string {
+	if x != nil {
+		return x.LastPrice
+	}
+	return ""
+}
+
+func (x *GetTradesResponse) GetLastTimestamp() int64 {
+	if x != nil {
+		return x.LastTimestamp
+	}
+	return 0
+}
+
+type GetRateHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`    // earliest timestamp, inclusive, 0 means no lower bound
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`        // latest timestamp, exclusive, 0 means no upper bound
+	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`  // page size, 0 means the default of 100, at most 1000
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"` // nextCursor of the previous page, empty for the first page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateHistoryRequest) Reset() {
+	*x = GetRateHistoryRequest{}
+	mi := &file_rates_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateHistoryRequest) ProtoMessage() {}
+
+func (x *GetRateHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRateHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRateHistoryRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *GetRateHistoryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetRateHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rates         []*Rate                `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`           // stored rates, newest first
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateHistoryResponse) Reset() {
+	*x = GetRateHistoryResponse{}
+	mi := &file_rates_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateHistoryResponse) ProtoMessage() {}
+
+func (x *GetRateHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRateHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRateHistoryResponse) GetRates() []*Rate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *GetRateHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetCandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`                           // 1m, 5m, 1h or 1d
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                  // start of the range, inclusive, widened to a whole interval
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                      // end of the range, exclusive, widened to a whole interval, 0 means now
+	FillGaps      bool                   `protobuf:"varint,5,opt,name=fillGaps,proto3" json:"fillGaps,omitempty"`                          // return empty intervals with the previous close instead of skipping them
+	Method        CalculationMethod      `protobuf:"varint,6,opt,name=method,proto3,enum=rates.CalculationMethod" json:"method,omitempty"` // method of the aggregated rates, unspecified means top
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesRequest) Reset() {
+	*x = GetCandlesRequest{}
+	mi := &file_rates_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesRequest) ProtoMessage() {}
+
+func (x *GetCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetCandlesRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetCandlesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetCandlesRequest) GetFillGaps() bool {
+	if x != nil {
+		return x.FillGaps
+	}
+	return false
+}
+
+func (x *GetCandlesRequest) GetMethod() CalculationMethod {
+	if x != nil {
+		return x.Method
+	}
+	return CalculationMethod_CALCULATION_METHOD_UNSPECIFIED
+}
+
+type OHLC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Open          string                 `protobuf:"bytes,1,opt,name=open,proto3" json:"open,omitempty"`
+	High          string                 `protobuf:"bytes,2,opt,name=high,proto3" json:"high,omitempty"`
+	Low           string                 `protobuf:"bytes,3,opt,name=low,proto3" json:"low,omitempty"`
+	Close         string                 `protobuf:"bytes,4,opt,name=close,proto3" json:"close,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OHLC) Reset() {
+	*x = OHLC{}
+	mi := &file_rates_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OHLC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OHLC) ProtoMessage() {}
+
+func (x *OHLC) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OHLC.ProtoReflect.Descriptor instead.
+func (*OHLC) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OHLC) GetOpen() string {
+	if x != nil {
+		return x.Open
+	}
+	return ""
+}
+
+func (x *OHLC) GetHigh() string {
+	if x != nil {
+		return x.High
+	}
+	return ""
+}
+
+func (x *OHLC) GetLow() string {
+	if x != nil {
+		return x.Low
+	}
+	return ""
+}
+
+func (x *OHLC) GetClose() string {
+	if x != nil {
+		return x.Close
+	}
+	return ""
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // start of the interval
+	Ask           *OHLC                  `protobuf:"bytes,2,opt,name=ask,proto3" json:"ask,omitempty"`
+	Bid           *OHLC                  `protobuf:"bytes,3,opt,name=bid,proto3" json:"bid,omitempty"`
+	Mid           *OHLC                  `protobuf:"bytes,4,opt,name=mid,proto3" json:"mid,omitempty"`
+	Count         int64                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"` // number of stored rates in the interval, 0 for a filled gap
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_rates_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Candle) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Candle) GetAsk() *OHLC {
+	if x != nil {
+		return x.Ask
+	}
+	return nil
+}
+
+func (x *Candle) GetBid() *OHLC {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *Candle) GetMid() *OHLC {
+	if x != nil {
+		return x.Mid
+	}
+	return nil
+}
+
+func (x *Candle) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetCandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesResponse) Reset() {
+	*x = GetCandlesResponse{}
+	mi := &file_rates_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesResponse) ProtoMessage() {}
+
+func (x *GetCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type GetRateAsOfRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // point in time, Unix seconds
+	Method        CalculationMethod      `protobuf:"varint,3,opt,name=method,proto3,enum=rates.CalculationMethod" json:"method,omitempty"` // method of the stored rate, unspecified means top
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateAsOfRequest) Reset() {
+	*x = GetRateAsOfRequest{}
+	mi := &file_rates_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateAsOfRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateAsOfRequest) ProtoMessage() {}
+
+func (x *GetRateAsOfRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateAsOfRequest.ProtoReflect.Descriptor instead.
+func (*GetRateAsOfRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRateAsOfRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *GetRateAsOfRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GetRateAsOfRequest) GetMethod() CalculationMethod {
+	if x != nil {
+		return x.Method
+	}
+	return CalculationMethod_CALCULATION_METHOD_UNSPECIFIED
+}
+
+type GetRateAsOfResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          *Rate                  `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"` // latest stored rate at or before the timestamp
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`    // identifier of the stored rate in the rates table
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateAsOfResponse) Reset() {
+	*x = GetRateAsOfResponse{}
+	mi := &file_rates_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateAsOfResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateAsOfResponse) ProtoMessage() {}
+
+func (x *GetRateAsOfResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateAsOfResponse.ProtoReflect.Descriptor instead.
+func (*GetRateAsOfResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetRateAsOfResponse) GetRate() *Rate {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+func (x *GetRateAsOfResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetLatestRateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	MaxAgeMs      int64                  `protobuf:"varint,2,opt,name=maxAgeMs,proto3" json:"maxAgeMs,omitempty"` // maximum age of a stored top rate, older ones are fetched live, 0 accepts any age
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRateRequest) Reset() {
+	*x = GetLatestRateRequest{}
+	mi := &file_rates_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRateRequest) ProtoMessage() {}
+
+func (x *GetLatestRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRateRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestRateRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetLatestRateRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *GetLatestRateRequest) GetMaxAgeMs() int64 {
+	if x != nil {
+		return x.MaxAgeMs
+	}
+	return 0
+}
+
+type GetLatestRateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          *Rate                  `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	Stored        bool                   `protobuf:"varint,2,opt,name=stored,proto3" json:"stored,omitempty"` // read from the stored rates rather than fetched from the exchange
+	AgeMs         int64                  `protobuf:"varint,3,opt,name=ageMs,proto3" json:"ageMs,omitempty"`   // time since the rate timestamp in milliseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRateResponse) Reset() {
+	*x = GetLatestRateResponse{}
+	mi := &file_rates_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRateResponse) ProtoMessage() {}
+
+func (x *GetLatestRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRateResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestRateResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetLatestRateResponse) GetRate() *Rate {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+func (x *GetLatestRateResponse) GetStored() bool {
+	if x != nil {
+		return x.Stored
+	}
+	return false
+}
+
+func (x *GetLatestRateResponse) GetAgeMs() int64 {
+	if x != nil {
+		return x.AgeMs
+	}
+	return 0
+}
+
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_rates_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{19}
+}
+
+type CircuitState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"` // depth provider, e.g. "grinex"
+	Market        string                 `protobuf:"bytes,2,opt,name=market,proto3" json:"market,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`  // closed, open or half-open
+	Since         int64                  `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"` // time of the last state change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CircuitState) Reset() {
+	*x = CircuitState{}
+	mi := &file_rates_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CircuitState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitState) ProtoMessage() {}
+
+func (x *CircuitState) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitState.ProtoReflect.Descriptor instead.
+func (*CircuitState) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CircuitState) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CircuitState) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *CircuitState) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CircuitState) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`     // ok, or degraded if a circuit is not closed
+	Circuits      []*CircuitState        `protobuf:"bytes,2,rep,name=circuits,proto3" json:"circuits,omitempty"` // circuits that have changed state since the start
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_rates_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rates_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_rates_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *HealthCheckResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) GetCircuits() []*CircuitState {
+	if x != nil {
+		return x.Circuits
+	}
+	return nil
+}
+
+var File_rates_proto protoreflect.FileDescriptor
+
+const file_rates_proto_rawDesc = "" +
+	"\n" +
+	"\vrates.proto\x12\x05rates\"\x9b\x01\n" +
+	"\x0fGetRatesRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\"\n" +
+	"\ftargetVolume\x18\x02 \x01(\tR\ftargetVolume\x120\n" +
+	"\x06method\x18\x03 \x01(\x0e2\x18.rates.CalculationMethodR\x06method\x12\f\n" +
+	"\x01n\x18\x04 \x01(\rR\x01n\x12\f\n" +
+	"\x01m\x18\x05 \x01(\rR\x01m\"\x88\x03\n" +
+	"\x04Rate\x12\x1a\n" +
+	"\baskPrice\x18\x01 \x01(\tR\baskPrice\x12\x1a\n" +
+	"\bbidPrice\x18\x02 \x01(\tR\bbidPrice\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x1e\n" +
+	"\n" +
+	"askPartial\x18\x04 \x01(\bR\n" +
+	"askPartial\x12\x1e\n" +
+	"\n" +
+	"bidPartial\x18\x05 \x01(\bR\n" +
+	"bidPartial\x120\n" +
+	"\x06method\x18\x06 \x01(\x0e2\x18.rates.CalculationMethodR\x06method\x12\x1a\n" +
+	"\bmidPrice\x18\a \x01(\tR\bmidPrice\x12\x16\n" +
+	"\x06spread\x18\b \x01(\tR\x06spread\x12\x1c\n" +
+	"\tspreadBps\x18\t \x01(\tR\tspreadBps\x12\x16\n" +
+	"\x06source\x18\n" +
+	" \x01(\tR\x06source\x12\x16\n" +
+	"\x06venues\x18\v \x03(\tR\x06venues\x12\x16\n" +
+	"\x06cached\x18\f \x01(\bR\x06cached\x12\x1e\n" +
+	"\n" +
+	"cacheAgeMs\x18\r \x01(\x03R\n" +
+	"cacheAgeMs\"3\n" +
+	"\x10GetRatesResponse\x12\x1f\n" +
+	"\x04rate\x18\x01 \x01(\v2\v.rates.RateR\x04rate\"V\n" +
+	"\x06Market\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04base\x18\x03 \x01(\tR\x04base\x12\x14\n" +
+	"\x05quote\x18\x04 \x01(\tR\x05quote\"\x14\n" +
+	"\x12ListMarketsRequest\">\n" +
+	"\x13ListMarketsResponse\x12'\n" +
+	"\amarkets\x18\x01 \x03(\v2\r.rates.MarketR\amarkets\"@\n" +
+	"\x10GetTradesRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\"\x8f\x01\n" +
+	"\x05Trade\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\tR\x05price\x12\x16\n" +
+	"\x06volume\x18\x03 \x01(\tR\x06volume\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12\x12\n" +
+	"\x04side\x18\x05 \x01(\tR\x04side\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"}\n" +
+	"\x11GetTradesResponse\x12$\n" +
+	"\x06trades\x18\x01 \x03(\v2\f.rates.TradeR\x06trades\x12\x1c\n" +
+	"\tlastPrice\x18\x02 \x01(\tR\tlastPrice\x12$\n" +
+	"\rlastTimestamp\x18\x03 \x01(\x03R\rlastTimestamp\"\x81\x01\n" +
+	"\x15GetRateHistoryRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"[\n" +
+	"\x16GetRateHistoryResponse\x12!\n" +
+	"\x05rates\x18\x01 \x03(\v2\v.rates.RateR\x05rates\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xb9\x01\n" +
+	"\x11GetCandlesRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x1a\n" +
+	"\bfillGaps\x18\x05 \x01(\bR\bfillGaps\x120\n" +
+	"\x06method\x18\x06 \x01(\x0e2\x18.rates.CalculationMethodR\x06method\"V\n" +
+	"\x04OHLC\x12\x12\n" +
+	"\x04open\x18\x01 \x01(\tR\x04open\x12\x12\n" +
+	"\x04high\x18\x02 \x01(\tR\x04high\x12\x10\n" +
+	"\x03low\x18\x03 \x01(\tR\x03low\x12\x14\n" +
+	"\x05close\x18\x04 \x01(\tR\x05close\"\x99\x01\n" +
+	"\x06Candle\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1d\n" +
+	"\x03ask\x18\x02 \x01(\v2\v.rates.OHLCR\x03ask\x12\x1d\n" +
+	"\x03bid\x18\x03 \x01(\v2\v.rates.OHLCR\x03bid\x12\x1d\n" +
+	"\x03mid\x18\x04 \x01(\v2\v.rates.OHLCR\x03mid\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x03R\x05count\"=\n" +
+	"\x12GetCandlesResponse\x12'\n" +
+	"\acandles\x18\x01 \x03(\v2\r.rates.CandleR\acandles\"|\n" +
+	"\x12GetRateAsOfRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x120\n" +
+	"\x06method\x18\x03 \x01(\x0e2\x18.rates.CalculationMethodR\x06method\"F\n" +
+	"\x13GetRateAsOfResponse\x12\x1f\n" +
+	"\x04rate\x18\x01 \x01(\v2\v.rates.RateR\x04rate\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"J\n" +
+	"\x14GetLatestRateRequest\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x1a\n" +
+	"\bmaxAgeMs\x18\x02 \x01(\x03R\bmaxAgeMs\"f\n" +
+	"\x15GetLatestRateResponse\x12\x1f\n" +
+	"\x04rate\x18\x01 \x01(\v2\v.rates.RateR\x04rate\x12\x16\n" +
+	"\x06stored\x18\x02 \x01(\bR\x06stored\x12\x14\n" +
+	"\x05ageMs\x18\x03 \x01(\x03R\x05ageMs\"\x14\n" +
+	"\x12HealthCheckRequest\"n\n" +
+	"\fCircuitState\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06market\x18\x02 \x01(\tR\x06market\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\x03R\x05since\"^\n" +
+	"\x13HealthCheckResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12/\n" +
+	"\bcircuits\x18\x02 \x03(\v2\x13.rates.CircuitStateR\bcircuits*\xad\x01\n" +
+	"\x11CalculationMethod\x12\"\n" +
+	"\x1eCALCULATION_METHOD_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16CALCULATION_METHOD_TOP\x10\x01\x12\x1c\n" +
+	"\x18CALCULATION_METHOD_TOP_N\x10\x02\x12\x1d\n" +
+	"\x19CALCULATION_METHOD_AVG_NM\x10\x03\x12\x1b\n" +
+	"\x17CALCULATION_METHOD_VWAP\x10\x042\xbb\x04\n" +
+	"\fRatesService\x12;\n" +
+	"\bGetRates\x12\x16.rates.GetRatesRequest\x1a\x17.rates.GetRatesResponse\x12D\n" +
+	"\vListMarkets\x12\x19.rates.ListMarketsRequest\x1a\x1a.rates.ListMarketsResponse\x12>\n" +
+	"\tGetTrades\x12\x17.rates.GetTradesRequest\x1a\x18.rates.GetTradesResponse\x12M\n" +
+	"\x0eGetRateHistory\x12\x1c.rates.GetRateHistoryRequest\x1a\x1d.rates.GetRateHistoryResponse\x12A\n" +
+	"\n" +
+	"GetCandles\x12\x18.rates.GetCandlesRequest\x1a\x19.rates.GetCandlesResponse\x12D\n" +
+	"\vGetRateAsOf\x12\x19.rates.GetRateAsOfRequest\x1a\x1a.rates.GetRateAsOfResponse\x12J\n" +
+	"\rGetLatestRate\x12\x1b.rates.GetLatestRateRequest\x1a\x1c.rates.GetLatestRateResponse\x12D\n" +
 	"\vHealthCheck\x12\x19.rates.HealthCheckRequest\x1a\x1a.rates.HealthCheckResponseB\x1fZ\x1dusdt-rate-service/internal/pbb\x06proto3"
 
 var (
@@ -281,25 +1599,70 @@ func file_rates_proto_rawDescGZIP() []byte {
 	return file_rates_proto_rawDescData
 }
 
-var file_rates_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_rates_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_rates_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_rates_proto_goTypes = []any{
-	(*GetRatesRequest)(nil),     // 0: rates.GetRatesRequest
-	(*Rate)(nil),                // 1: rates.Rate
-	(*GetRatesResponse)(nil),    // 2: rates.GetRatesResponse
-	(*HealthCheckRequest)(nil),  // 3: rates.HealthCheckRequest
-	(*HealthCheckResponse)(nil), // 4: rates.HealthCheckResponse
+	(CalculationMethod)(0),         // 0: rates.CalculationMethod
+	(*GetRatesRequest)(nil),        // 1: rates.GetRatesRequest
+	(*Rate)(nil),                   // 2: rates.Rate
+	(*GetRatesResponse)(nil),       // 3: rates.GetRatesResponse
+	(*Market)(nil),                 // 4: rates.Market
+	(*ListMarketsRequest)(nil),     // 5: rates.ListMarketsRequest
+	(*ListMarketsResponse)(nil),    // 6: rates.ListMarketsResponse
+	(*GetTradesRequest)(nil),       // 7: rates.GetTradesRequest
+	(*Trade)(nil),                  // 8: rates.Trade
+	(*GetTradesResponse)(nil),      // 9: rates.GetTradesResponse
+	(*GetRateHistoryRequest)(nil),  // 10: rates.GetRateHistoryRequest
+	(*GetRateHistoryResponse)(nil), // 11: rates.GetRateHistoryResponse
+	(*GetCandlesRequest)(nil),      // 12: rates.GetCandlesRequest
+	(*OHLC)(nil),                   // 13: rates.OHLC
+	(*Candle)(nil),                 // 14: rates.Candle
+	(*GetCandlesResponse)(nil),     // 15: rates.GetCandlesResponse
+	(*GetRateAsOfRequest)(nil),     // 16: rates.GetRateAsOfRequest
+	(*GetRateAsOfResponse)(nil),    // 17: rates.GetRateAsOfResponse
+	(*GetLatestRateRequest)(nil),   // 18: rates.GetLatestRateRequest
+	(*GetLatestRateResponse)(nil),  // 19: rates.GetLatestRateResponse
+	(*HealthCheckRequest)(nil),     // 20: rates.HealthCheckRequest
+	(*CircuitState)(nil),           // 21: rates.CircuitState
+	(*HealthCheckResponse)(nil),    // 22: rates.HealthCheckResponse
 }
 var file_rates_proto_depIdxs = []int32{
-	1, // 0: rates.GetRatesResponse.rate:type_name -> rates.Rate
-	0, // 1: rates.RatesService.GetRates:input_type -> rates.GetRatesRequest
-	3, // 2: rates.RatesService.HealthCheck:input_type -> rates.HealthCheckRequest
-	2, // 3: rates.RatesService.GetRates:output_type -> rates.GetRatesResponse
-	4, // 4: rates.RatesService.HealthCheck:output_type -> rates.HealthCheckResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: rates.GetRatesRequest.method:type_name -> rates.CalculationMethod
+	0,  // 1: rates.Rate.method:type_name -> rates.CalculationMethod
+	2,  // 2: rates.GetRatesResponse.rate:type_name -> rates.Rate
+	4,  // 3: rates.ListMarketsResponse.markets:type_name -> rates.Market
+	8,  // 4: rates.GetTradesResponse.trades:type_name -> rates.Trade
+	2,  // 5: rates.GetRateHistoryResponse.rates:type_name -> rates.Rate
+	0,  // 6: rates.GetCandlesRequest.method:type_name -> rates.CalculationMethod
+	13, // 7: rates.Candle.ask:type_name -> rates.OHLC
+	13, // 8: rates.Candle.bid:type_name -> rates.OHLC
+	13, // 9: rates.Candle.mid:type_name -> rates.OHLC
+	14, // 10: rates.GetCandlesResponse.candles:type_name -> rates.Candle
+	0,  // 11: rates.GetRateAsOfRequest.method:type_name -> rates.CalculationMethod
+	2,  // 12: rates.GetRateAsOfResponse.rate:type_name -> rates.Rate
+	2,  // 13: rates.GetLatestRateResponse.rate:type_name -> rates.Rate
+	21, // 14: rates.HealthCheckResponse.circuits:type_name -> rates.CircuitState
+	1,  // 15: rates.RatesService.GetRates:input_type -> rates.GetRatesRequest
+	5,  // 16: rates.RatesService.ListMarkets:input_type -> rates.ListMarketsRequest
+	7,  // 17: rates.RatesService.GetTrades:input_type -> rates.GetTradesRequest
+	10, // 18: rates.RatesService.GetRateHistory:input_type -> rates.GetRateHistoryRequest
+	12, // 19: rates.RatesService.GetCandles:input_type -> rates.GetCandlesRequest
+	16, // 20: rates.RatesService.GetRateAsOf:input_type -> rates.GetRateAsOfRequest
+	18, // 21: rates.RatesService.GetLatestRate:input_type -> rates.GetLatestRateRequest
+	20, // 22: rates.RatesService.HealthCheck:input_type -> rates.HealthCheckRequest
+	3,  // 23: rates.RatesService.GetRates:output_type -> rates.GetRatesResponse
+	6,  // 24: rates.RatesService.ListMarkets:output_type -> rates.ListMarketsResponse
+	9,  // 25: rates.RatesService.GetTrades:output_type -> rates.GetTradesResponse
+	11, // 26: rates.RatesService.GetRateHistory:output_type -> rates.GetRateHistoryResponse
+	15, // 27: rates.RatesService.GetCandles:output_type -> rates.GetCandlesResponse
+	17, // 28: rates.RatesService.GetRateAsOf:output_type -> rates.GetRateAsOfResponse
+	19, // 29: rates.RatesService.GetLatestRate:output_type -> rates.GetLatestRateResponse
+	22, // 30: rates.RatesService.HealthCheck:output_type -> rates.HealthCheckResponse
+	23, // [23:31] is the sub-list for method output_type
+	15, // [15:23] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_rates_proto_init() }
@@ -312,13 +1675,14 @@ func file_rates_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rates_proto_rawDesc), len(file_rates_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_rates_proto_goTypes,
 		DependencyIndexes: file_rates_proto_depIdxs,
+		EnumInfos:         file_rates_proto_enumTypes,
 		MessageInfos:      file_rates_proto_msgTypes,
 	}.Build()
 	File_rates_proto = out.File
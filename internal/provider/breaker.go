@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets requests through and counts consecutive failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects requests until the cooldown elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe request through to decide whether to close or reopen.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state as used in logs.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerSettings configures when a circuit breaker opens and for how long.
+type BreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker, zero disables it.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before a probe request is let through.
+	Cooldown time.Duration
+}
+
+// StateChangeFunc is called on every state transition of a market circuit.
+// It is called with the breaker locked and must not block or call back into the breaker.
+type StateChangeFunc func(market string, from, to BreakerState)
+
+// CircuitBreaker is a DepthProvider decorator that stops calling a failing provider.
+//
+// Every market has its own circuit. After FailureThreshold consecutive failures the circuit opens
+// and requests fail fast with an error wrapping models.ErrCircuitOpen. Once the cooldown elapses
+// a single probe request is let through: its success closes the circuit, its failure opens it again.
+// Requests canceled or timed out by the caller and requests refused by a local rate limiter
+// are not counted as failures.
+type CircuitBreaker struct {
+	logger         *zap.Logger
+	name           string
+	provider       service.DepthProvider
+	settings       BreakerSettings
+	marketSettings map[string]BreakerSettings
+	onStateChange  StateChangeFunc
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of the breaker for one market.
+type circuit struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// BreakerOption configures optional CircuitBreaker settings.
+type BreakerOption func(*CircuitBreaker)
+
+// WithMarketBreakerSettings overrides the breaker settings for a specific market.
+func WithMarketBreakerSettings(market string, settings BreakerSettings) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.marketSettings[market] = settings
+	}
+}
+
+// WithStateChangeHook sets a function called on every state transition, e.g. to export it for alerting.
+func WithStateChangeHook(hook StateChangeFunc) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.onStateChange = hook
+	}
+}
+
+// NewCircuitBreaker wraps the named provider in a circuit breaker with the given default settings.
+func NewCircuitBreaker(
+	logger *zap.Logger,
+	name string,
+	provider service.DepthProvider,
+	settings BreakerSettings,
+	opts ...BreakerOption,
+) *CircuitBreaker {
+	b := &CircuitBreaker{
+		logger:         logger,
+		name:           name,
+		provider:       provider,
+		settings:       settings,
+		marketSettings: make(map[string]BreakerSettings),
+		circuits:       make(map[string]*circuit),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// GetDepth calls the wrapped provider unless the circuit of the market is open.
+// It returns an error wrapping models.ErrCircuitOpen without calling the provider if it is.
+func (b *CircuitBreaker) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	if err := b.allow(market); err != nil {
+		return nil, err
+	}
+	depth, err := b.provider.GetDepth(ctx, market)
+	b.record(market, err, ctx.Err() != nil)
+	return depth, err
+}
+
+// State returns the current state of the circuit of the market.
+func (b *CircuitBreaker) State(market string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[market]; ok {
+		return c.state
+	}
+	return BreakerClosed
+}
+
+// allow reports whether a request for the market may be sent, moving an open circuit
+// whose cooldown has elapsed to half-open.
+func (b *CircuitBreaker) allow(market string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(market)
+	switch c.state {
+	case BreakerClosed:
+		return nil
+	case BreakerOpen:
+		wait := b.settingsFor(market).Cooldown - time.Since(c.openedAt)
+		if wait > 0 {
+			return fmt.Errorf("%w: %s for market %s, retry in %s",
+				models.ErrCircuitOpen, b.name, market, wait.Round(time.Millisecond))
+		}
+		b.transition(market, c, BreakerHalfOpen, nil)
+	case BreakerHalfOpen:
+		if c.probing {
+			return fmt.Errorf("%w: %s for market %s, probe in progress", models.ErrCircuitOpen, b.name, market)
+		}
+	}
+	c.probing = true
+	return nil
+}
+
+// record updates the circuit of the market with the outcome of a request.
+// callerDone reports whether the context of the caller was canceled or timed out by the time the request ended.
+func (b *CircuitBreaker) record(market string, err error, callerDone bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(market)
+	switch {
+	case err != nil && callerDone, errors.Is(err, context.Canceled), errors.Is(err, models.ErrRateLimited):
+		// The caller gave up or the provider was not called, this says nothing about the provider.
+		c.probing = false
+	case err == nil:
+		c.failures = 0
+		if c.state == BreakerHalfOpen {
+			b.transition(market, c, BreakerClosed, nil)
+		}
+	default:
+		c.failures++
+		threshold := b.settingsFor(market).FailureThreshold
+		if c.state == BreakerHalfOpen || (c.state == BreakerClosed && threshold > 0 && c.failures >= threshold) {
+			c.openedAt = time.Now()
+			b.transition(market, c, BreakerOpen, err)
+		}
+	}
+}
+
+// transition moves the circuit to the new state, logs it and notifies the hook.
+func (b *CircuitBreaker) transition(market string, c *circuit, to BreakerState, cause error) {
+	from := c.state
+	c.state = to
+	c.probing = false
+	if to == BreakerClosed {
+		c.failures = 0
+	}
+
+	fields := []zap.Field{
+		zap.String("provider", b.name),
+		zap.String("market", market),
+		zap.Stringer("from", from),
+		zap.Stringer("to", to),
+	}
+	if to == BreakerOpen {
+		b.logger.Warn("Circuit breaker opened", append(fields,
+			zap.Int("failures", c.failures),
+			zap.Duration("cooldown", b.settingsFor(market).Cooldown),
+			zap.Error(cause),
+		)...)
+	} else {
+		b.logger.Info("Circuit breaker state changed", fields...)
+	}
+	if b.onStateChange != nil {
+		b.onStateChange(market, from, to)
+	}
+}
+
+// circuit returns the circuit of the market, creating a closed one on first use.
+func (b *CircuitBreaker) circuit(market string) *circuit {
+	c, ok := b.circuits[market]
+	if !ok {
+		c = &circuit{state: BreakerClosed}
+		b.circuits[market] = c
+	}
+	return c
+}
+
+// settingsFor returns the breaker settings of the market.
+func (b *CircuitBreaker) settingsFor(market string) BreakerSettings {
+	if settings, ok := b.marketSettings[market]; ok {
+		return settings
+	}
+	return b.settings
+}
+
+// CircuitStates collects the circuit states of several breakers from their state change hooks,
+// so that they can be reported, e.g. by the health check.
+type CircuitStates struct {
+	mu     sync.Mutex
+	states map[[2]string]models.CircuitState
+}
+
+// NewCircuitStates creates a new, empty CircuitStates.
+func NewCircuitStates() *CircuitStates {
+	return &CircuitStates{states: make(map[[2]string]models.CircuitState)}
+}
+
+// Hook returns the state change hook to set with WithStateChangeHook on the breaker of the named provider.
+func (s *CircuitStates) Hook(provider string) StateChangeFunc {
+	return func(market string, _, to BreakerState) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.states[[2]string{provider, market}] = models.CircuitState{
+			Provider: provider,
+			Market:   market,
+			State:    to.String(),
+			Since:    time.Now(),
+		}
+	}
+}
+
+// CircuitStates returns the states of the circuits that have changed state at least once,
+// sorted by provider and market. Circuits that never left the closed state are not listed.
+func (s *CircuitStates) CircuitStates() []models.CircuitState {
+	s.mu.Lock()
+	states := make([]models.CircuitState, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state)
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(states, func(a, b models.CircuitState) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Market, b.Market))
+	})
+	return states
+}
@@ -0,0 +1,174 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCircuitBreaker_GetDepth(t *testing.T) {
+	ctx := context.Background()
+	errUpstream := errors.New("connection reset")
+	settings := provider.BreakerSettings{FailureThreshold: 2, Cooldown: 50 * time.Millisecond}
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Twice()
+
+		var transitions []string
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, settings,
+			provider.WithStateChangeHook(func(market string, from, to provider.BreakerState) {
+				transitions = append(transitions, market+": "+from.String()+" -> "+to.String())
+			}),
+		)
+
+		for range 2 {
+			_, err := breaker.GetDepth(ctx, "usdtrub")
+			require.ErrorIs(t, err, errUpstream)
+		}
+		_, err := breaker.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, models.ErrCircuitOpen)
+		assert.Equal(t, provider.BreakerOpen, breaker.State("usdtrub"))
+		assert.Equal(t, provider.BreakerClosed, breaker.State("btcusdt"))
+		assert.Equal(t, []string{"usdtrub: closed -> open"}, transitions)
+	})
+
+	t.Run("success resets the failure count", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Once()
+		upstream.On("GetDepth", ctx, "usdtrub").Return(validDepth("100", "99"), nil).Once()
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Once()
+
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, settings)
+		for range 3 {
+			_, _ = breaker.GetDepth(ctx, "usdtrub")
+		}
+		assert.Equal(t, provider.BreakerClosed, breaker.State("usdtrub"))
+	})
+
+	t.Run("probe after cooldown closes or reopens", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Times(3)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(validDepth("100", "99"), nil).Once()
+
+		var transitions []string
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, settings,
+			provider.WithStateChangeHook(func(_ string, from, to provider.BreakerState) {
+				transitions = append(transitions, from.String()+" -> "+to.String())
+			}),
+		)
+		for range 2 {
+			_, _ = breaker.GetDepth(ctx, "usdtrub")
+		}
+
+		time.Sleep(settings.Cooldown)
+		_, err := breaker.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, errUpstream)
+		_, err = breaker.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, models.ErrCircuitOpen)
+
+		time.Sleep(settings.Cooldown)
+		_, err = breaker.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, provider.BreakerClosed, breaker.State("usdtrub"))
+		assert.Equal(t, []string{
+			"closed -> open", "open -> half-open", "half-open -> open", "open -> half-open", "half-open -> closed",
+		}, transitions)
+	})
+
+	t.Run("per-market settings and canceled requests", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", canceled, "usdtrub").Return(nil, context.Canceled).Twice()
+		upstream.On("GetDepth", ctx, "btcusdt").Return(nil, errUpstream).Once()
+
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, settings,
+			provider.WithMarketBreakerSettings("btcusdt", provider.BreakerSettings{
+				FailureThreshold: 1,
+				Cooldown:         time.Minute,
+			}),
+		)
+		for range 2 {
+			_, err := breaker.GetDepth(canceled, "usdtrub")
+			require.ErrorIs(t, err, context.Canceled)
+		}
+		assert.Equal(t, provider.BreakerClosed, breaker.State("usdtrub"))
+
+		_, _ = breaker.GetDepth(ctx, "btcusdt")
+		_, err := breaker.GetDepth(ctx, "btcusdt")
+		require.ErrorIs(t, err, models.ErrCircuitOpen)
+		assert.Contains(t, err.Error(), "grinex for market btcusdt")
+	})
+
+	t.Run("local rate limiting is not a failure", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, models.ErrRateLimited).Times(3)
+
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, settings)
+		for range 3 {
+			_, err := breaker.GetDepth(ctx, "usdtrub")
+			require.ErrorIs(t, err, models.ErrRateLimited)
+		}
+		assert.Equal(t, provider.BreakerClosed, breaker.State("usdtrub"))
+	})
+
+	t.Run("zero threshold never opens", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Times(5)
+
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream, provider.BreakerSettings{})
+		for range 5 {
+			_, err := breaker.GetDepth(ctx, "usdtrub")
+			require.ErrorIs(t, err, errUpstream)
+		}
+	})
+}
+
+func TestCircuitBreaker_CallerDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	upstream := mocks.NewMockDepthProvider(t)
+	upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errors.New("read: i/o timeout")).Twice()
+
+	breaker := provider.NewCircuitBreaker(zap.NewNop(), "grinex", upstream,
+		provider.BreakerSettings{FailureThreshold: 1, Cooldown: time.Minute})
+	for range 2 {
+		_, err := breaker.GetDepth(ctx, "usdtrub")
+		require.EqualError(t, err, "read: i/o timeout")
+	}
+	assert.Equal(t, provider.BreakerClosed, breaker.State("usdtrub"))
+}
+
+func TestCircuitStates(t *testing.T) {
+	ctx := context.Background()
+	upstream := mocks.NewMockDepthProvider(t)
+	upstream.On("GetDepth", ctx, mock.Anything).Return(nil, errors.New("connection reset"))
+
+	circuits := provider.NewCircuitStates()
+	settings := provider.BreakerSettings{FailureThreshold: 1, Cooldown: time.Minute}
+	for _, name := range []string{"rest", "grinex"} {
+		breaker := provider.NewCircuitBreaker(zap.NewNop(), name, upstream, settings,
+			provider.WithStateChangeHook(circuits.Hook(name)))
+		_, _ = breaker.GetDepth(ctx, "usdtrub")
+	}
+	assert.Empty(t, provider.NewCircuitStates().CircuitStates())
+
+	states := circuits.CircuitStates()
+	require.Len(t, states, 2)
+	assert.Equal(t, "grinex", states[0].Provider)
+	assert.Equal(t, "usdtrub", states[0].Market)
+	assert.Equal(t, "open", states[0].State)
+	assert.WithinDuration(t, time.Now(), states[0].Since, time.Second)
+	assert.Equal(t, "rest", states[1].Provider)
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultFetchTimeout bounds a shared upstream fetch of a Cache without a fetch timeout configured.
+const defaultFetchTimeout = 30 * time.Second
+
+// Cache is a DepthProvider decorator that keeps the last valid depth data of every market for a TTL
+// and coalesces concurrent requests for the same market into a single upstream fetch.
+//
+// Depth data served from the cache, or shared with a concurrent request that fetched it,
+// is marked as cached and carries its age. Every copy of the depth data of a fetch carries the time
+// of that fetch. Errors and invalid depth data are not cached.
+type Cache struct {
+	logger    *zap.Logger
+	provider  service.DepthProvider
+	ttl       time.Duration
+	marketTTL map[string]time.Duration
+	// fetchTimeout bounds a shared upstream fetch, which no caller's deadline applies to.
+	fetchTimeout time.Duration
+	group        singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is the depth data of a market and the time it was fetched.
+type cacheEntry struct {
+	depth     *models.Depth
+	fetchedAt time.Time
+}
+
+// CacheOption configures optional Cache settings.
+type CacheOption func(*Cache)
+
+// WithMarketTTL overrides the TTL for a specific market.
+func WithMarketTTL(market string, ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.marketTTL[market] = ttl
+	}
+}
+
+// WithFetchTimeout sets how long a shared upstream fetch may take, 30s by default.
+// A non-positive timeout keeps the default.
+func WithFetchTimeout(timeout time.Duration) CacheOption {
+	return func(c *Cache) {
+		if timeout > 0 {
+			c.fetchTimeout = timeout
+		}
+	}
+}
+
+// NewCache wraps the provider in a cache with the given default TTL.
+// A zero TTL disables caching but still coalesces concurrent requests.
+func NewCache(logger *zap.Logger, provider service.DepthProvider, ttl time.Duration, opts ...CacheOption) *Cache {
+	c := &Cache{
+		logger:       logger,
+		provider:     provider,
+		ttl:          ttl,
+		marketTTL:    make(map[string]time.Duration),
+		fetchTimeout: defaultFetchTimeout,
+		entries:      make(map[string]cacheEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetDepth returns the cached depth data of the market if it is younger than the TTL
+// and fetches it from the wrapped provider otherwise.
+func (c *Cache) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	if entry, ok := c.lookup(market); ok {
+		c.logger.Debug("Depth served from cache", zap.String("market", market))
+		return entry.result(true), nil
+	}
+
+	// The fetch is shared with the callers that join it, so neither the cancellation nor the deadline
+	// of the caller that started it applies to it: it is bounded by the fetch timeout instead, lest a fetch
+	// waiting for the rate limiter or retrying hold the market forever, and every caller waits for it
+	// only as long as its own context allows.
+	var leader bool
+	ch := c.group.DoChan(market, func() (any, error) {
+		leader = true
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.fetchTimeout)
+		defer cancel()
+		return c.fetch(fetchCtx, market)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		entry, _ := res.Val.(cacheEntry)
+		return entry.result(!leader), nil
+	}
+}
+
+// lookup returns the entry of the market if it has not expired.
+func (c *Cache) lookup(market string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[market]
+	if !ok || time.Since(entry.fetchedAt) >= c.ttlFor(market) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// fetch gets the depth data of the market from the wrapped provider and caches it if it is valid.
+func (c *Cache) fetch(ctx context.Context, market string) (cacheEntry, error) {
+	depth, err := c.provider.GetDepth(ctx, market)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	entry := cacheEntry{depth: depth, fetchedAt: time.Now()}
+	if depth.Validate() == nil && c.ttlFor(market) > 0 {
+		c.mu.Lock()
+		c.entries[market] = entry
+		c.mu.Unlock()
+	}
+	return entry, nil
+}
+
+// ttlFor returns the TTL of the market.
+func (c *Cache) ttlFor(market string) time.Duration {
+	if ttl, ok := c.marketTTL[market]; ok {
+		return ttl
+	}
+	return c.ttl
+}
+
+// result returns a copy of the cached depth data with the time of the fetch,
+// marked as cached with its age if cached is true.
+func (e cacheEntry) result(cached bool) *models.Depth {
+	depth := *e.depth
+	depth.FetchedAt = e.fetchedAt
+	if cached {
+		depth.Cached = true
+		depth.CacheAge = time.Since(e.fetchedAt)
+	}
+	return &depth
+}
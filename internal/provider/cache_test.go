@@ -0,0 +1,176 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCache_GetDepth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves fresh data from the cache", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").Return(validDepth("100", "99"), nil).Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute)
+		fetched, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.False(t, fetched.Cached)
+		assert.False(t, fetched.FetchedAt.IsZero())
+
+		depth, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.True(t, depth.Cached)
+		assert.Positive(t, depth.CacheAge)
+		assert.Equal(t, fetched.FetchedAt, depth.FetchedAt, "copies of a fetch carry its time")
+	})
+
+	t.Run("refetches expired data", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").Return(validDepth("100", "99"), nil).Twice()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute,
+			provider.WithMarketTTL("usdtrub", 10*time.Millisecond))
+		_, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		depth, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.False(t, depth.Cached)
+	})
+
+	t.Run("does not cache errors and invalid data", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").Return(nil, errors.New("connection reset")).Once()
+		upstream.On("GetDepth", mock.Anything, "usdtrub").Return(validDepth("99", "100"), nil).Once()
+		upstream.On("GetDepth", mock.Anything, "usdtrub").Return(validDepth("100", "99"), nil).Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute)
+		_, err := cache.GetDepth(ctx, "usdtrub")
+		require.Error(t, err)
+		_, err = cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		depth, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.False(t, depth.Cached)
+	})
+
+	t.Run("coalesces concurrent requests", func(t *testing.T) {
+		const clients = 10
+		release := make(chan struct{})
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").
+			Run(func(mock.Arguments) { <-release }).
+			Return(validDepth("100", "99"), nil).
+			Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, 0)
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			cached int
+		)
+		for range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				depth, err := cache.GetDepth(ctx, "usdtrub")
+				assert.NoError(t, err)
+				if err == nil && depth.Cached {
+					mu.Lock()
+					cached++
+					mu.Unlock()
+				}
+			}()
+		}
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, clients-1, cached)
+	})
+
+	t.Run("caller deadline does not bound the shared fetch", func(t *testing.T) {
+		release := make(chan struct{})
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").
+			Run(func(args mock.Arguments) {
+				fetchCtx, _ := args.Get(0).(context.Context)
+				deadline, ok := fetchCtx.Deadline()
+				assert.True(t, ok)
+				assert.Greater(t, time.Until(deadline), time.Second, "the fetch timeout applies instead")
+				<-release
+			}).
+			Return(validDepth("100", "99"), nil).
+			Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute)
+		short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		leader := make(chan error)
+		go func() {
+			_, err := cache.GetDepth(short, "usdtrub")
+			leader <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+		follower := make(chan error)
+		go func() {
+			_, err := cache.GetDepth(ctx, "usdtrub")
+			follower <- err
+		}()
+
+		require.ErrorIs(t, <-leader, context.DeadlineExceeded)
+		close(release)
+		require.NoError(t, <-follower)
+	})
+
+	t.Run("fetch timeout bounds the shared fetch", func(t *testing.T) {
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").
+			Return(func(fetchCtx context.Context, _ string) (*models.Depth, error) {
+				<-fetchCtx.Done()
+				return nil, fetchCtx.Err()
+			}, nil).
+			Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute, provider.WithFetchTimeout(20*time.Millisecond))
+		_, err := cache.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("caller cancellation does not cancel the shared fetch", func(t *testing.T) {
+		release := make(chan struct{})
+		upstream := mocks.NewMockDepthProvider(t)
+		upstream.On("GetDepth", mock.Anything, "usdtrub").
+			Run(func(mock.Arguments) { <-release }).
+			Return(validDepth("100", "99"), nil).
+			Once()
+
+		cache := provider.NewCache(zap.NewNop(), upstream, time.Minute)
+		canceled, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			_, err := cache.GetDepth(canceled, "usdtrub")
+			done <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+
+		close(release)
+		depth, err := cache.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, "100", depth.Asks[0].Price.String())
+	})
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"usdt-rate-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// ConsensusSource is the source name recorded in the depth data returned by Consensus.
+const ConsensusSource = "consensus"
+
+// Aggregation is the way Consensus combines the best prices of the venues.
+type Aggregation string
+
+const (
+	// AggregationMedian uses the median of the best prices.
+	AggregationMedian Aggregation = "median"
+	// AggregationWeighted uses the average of the best prices weighted by their volume.
+	AggregationWeighted Aggregation = "weighted"
+)
+
+const (
+	defaultMaxDeviationBps = 100
+	basisPoints            = 10000
+)
+
+// Consensus is a DepthProvider that queries every venue concurrently and combines their best ask
+// and bid prices into a single consensus level. Venues whose mid price deviates from the median mid price
+// by more than the maximum deviation are excluded.
+//
+// The returned depth data has one level on each side holding the combined price and the total volume
+// of the contributing venues, its timestamp is the oldest one among them and its Venues lists them.
+type Consensus struct {
+	logger          *zap.Logger
+	venues          []Named
+	aggregation     Aggregation
+	maxDeviationBps int64
+	minVenues       int
+}
+
+// ConsensusOption configures optional Consensus settings.
+type ConsensusOption func(*Consensus)
+
+// WithAggregation sets the way the best prices are combined. The default is AggregationMedian.
+func WithAggregation(aggregation Aggregation) ConsensusOption {
+	return func(c *Consensus) {
+		c.aggregation = aggregation
+	}
+}
+
+// WithMaxDeviationBps sets the maximum deviation of a venue mid price from the median mid price
+// in basis points. The default is 100 bps.
+func WithMaxDeviationBps(bps int64) ConsensusOption {
+	return func(c *Consensus) {
+		c.maxDeviationBps = bps
+	}
+}
+
+// WithMinVenues sets the minimum number of venues that must contribute to the consensus. The default is 1.
+func WithMinVenues(n int) ConsensusOption {
+	return func(c *Consensus) {
+		c.minVenues = n
+	}
+}
+
+// NewConsensus creates a new Consensus over the given venues.
+func NewConsensus(logger *zap.Logger, venues []Named, opts ...ConsensusOption) *Consensus {
+	c := &Consensus{
+		logger:          logger,
+		venues:          venues,
+		aggregation:     AggregationMedian,
+		maxDeviationBps: defaultMaxDeviationBps,
+		minVenues:       1,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// venueQuote is the top of the book of one venue.
+type venueQuote struct {
+	venue     string
+	timestamp int64
+	ask       models.Order
+	bid       models.Order
+	mid       models.Decimal
+}
+
+// GetDepth queries all venues concurrently and returns the consensus depth data.
+// It returns an error wrapping models.ErrAllProvidersFailed if no venue answered with valid data
+// and models.ErrNoConsensus if fewer than the minimum number of venues remain after excluding outliers.
+func (c *Consensus) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	logger := c.logger.With(zap.String("provider", ConsensusSource), zap.String("market", market))
+
+	quotes, errs := c.collect(ctx, market)
+	for _, err := range errs {
+		logger.Warn("Venue excluded from consensus", zap.Error(err))
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("%w: %w", models.ErrAllProvidersFailed, errors.Join(errs...))
+	}
+
+	quotes = c.excludeOutliers(logger, quotes)
+	if len(quotes) < c.minVenues {
+		return nil, fmt.Errorf("%w: %d venues agree, %d required", models.ErrNoConsensus, len(quotes), c.minVenues)
+	}
+
+	depth := &models.Depth{
+		Timestamp: quotes[0].timestamp,
+		Asks:      []models.Order{c.combine(quotes, func(q venueQuote) models.Order { return q.ask })},
+		Bids:      []models.Order{c.combine(quotes, func(q venueQuote) models.Order { return q.bid })},
+		Source:    ConsensusSource,
+	}
+	for _, q := range quotes {
+		depth.Timestamp = min(depth.Timestamp, q.timestamp)
+		depth.Venues = append(depth.Venues, q.venue)
+	}
+	logger.Debug("Consensus depth", zap.Strings("venues", depth.Venues))
+	return depth, nil
+}
+
+// collect queries all venues concurrently and returns the quotes of those that answered with valid data,
+// in the order of the venues, and the errors of the others.
+func (c *Consensus) collect(ctx context.Context, market string) ([]venueQuote, []error) {
+	quotes := make([]*venueQuote, len(c.venues))
+	errs := make([]error, len(c.venues))
+
+	var wg sync.WaitGroup
+	for i, venue := range c.venues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			depth, err := venue.Provider.GetDepth(ctx, market)
+			if err == nil {
+				err = depth.Validate()
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", venue.Name, err)
+				return
+			}
+			quotes[i] = &venueQuote{
+				venue:     venue.Name,
+				timestamp: depth.Timestamp,
+				ask:       depth.Asks[0],
+				bid:       depth.Bids[0],
+				mid:       depth.Asks[0].Price.Add(depth.Bids[0].Price).Div(models.NewDecimalFromInt(2)),
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := make([]venueQuote, 0, len(quotes))
+	for _, q := range quotes {
+		if q != nil {
+			result = append(result, *q)
+		}
+	}
+	return result, slices.DeleteFunc(errs, func(err error) bool { return err == nil })
+}
+
+// excludeOutliers drops the quotes whose mid price deviates from the median mid price
+// by more than the maximum deviation.
+func (c *Consensus) excludeOutliers(logger *zap.Logger, quotes []venueQuote) []venueQuote {
+	mids := make([]models.Decimal, len(quotes))
+	for i, q := range quotes {
+		mids[i] = q.mid
+	}
+	median := medianOf(mids)
+	maxDeviation := median.Mul(models.NewDecimalFromInt(c.maxDeviationBps)).Div(models.NewDecimalFromInt(basisPoints))
+
+	return slices.DeleteFunc(quotes, func(q venueQuote) bool {
+		deviation := q.mid.Sub(median).Abs()
+		if deviation.Cmp(maxDeviation) <= 0 {
+			return false
+		}
+		logger.Warn("Venue deviates too far from the others, excluded from consensus",
+			zap.String("venue", q.venue),
+			zap.Stringer("mid", q.mid),
+			zap.Stringer("medianMid", median),
+		)
+		return true
+	})
+}
+
+// combine combines one side of the quotes into a single level with the total volume of the venues.
+func (c *Consensus) combine(quotes []venueQuote, side func(venueQuote) models.Order) models.Order {
+	var (
+		volume   models.Decimal
+		notional models.Decimal
+		prices   = make([]models.Decimal, len(quotes))
+	)
+	for i, q := range quotes {
+		order := side(q)
+		prices[i] = order.Price
+		volume = volume.Add(order.Volume)
+		notional = notional.Add(order.Price.Mul(order.Volume))
+	}
+
+	price := medianOf(prices)
+	if c.aggregation == AggregationWeighted {
+		price = notional.Div(volume)
+	}
+	return models.Order{
+		Price:  price,
+		Volume: volume,
+		Amount: price.Mul(volume),
+	}
+}
+
+// medianOf returns the median of the values, the average of the two middle ones for an even count.
+func medianOf(values []models.Decimal) models.Decimal {
+	sorted := slices.Clone(values)
+	slices.SortFunc(sorted, models.Decimal.Cmp)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return sorted[middle-1].Add(sorted[middle]).Div(models.NewDecimalFromInt(2))
+}
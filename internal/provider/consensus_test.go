@@ -0,0 +1,111 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/adapter"
+	"usdt-rate-service/internal/infra/grinex"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newFakeExchange starts a Grinex-like exchange serving a one-level book and returns a provider for it.
+func newFakeExchange(t *testing.T, name, ask, askVolume, bid, bidVolume string) provider.Named {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(grinex.DepthResponse{
+			Timestamp: time.Now().Unix(),
+			Asks:      []grinex.Order{{Price: ask, Volume: askVolume, Type: "limit"}},
+			Bids:      []grinex.Order{{Price: bid, Volume: bidVolume, Type: "limit"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return newExchangeProvider(t, name, server.URL)
+}
+
+// newBrokenExchange starts an exchange that always fails and returns a provider for it.
+func newBrokenExchange(t *testing.T, name string) provider.Named {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+	return newExchangeProvider(t, name, server.URL)
+}
+
+func newExchangeProvider(t *testing.T, name, url string) provider.Named {
+	t.Helper()
+	client, err := grinex.NewClient(url)
+	require.NoError(t, err)
+	return provider.Named{Name: name, Provider: adapter.NewGrinexDepthProvider(client)}
+}
+
+func TestConsensus_GetDepth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("median", func(t *testing.T) {
+		consensus := provider.NewConsensus(zap.NewNop(), []provider.Named{
+			newFakeExchange(t, "a", "100.0", "1", "99.0", "1"),
+			newFakeExchange(t, "b", "100.2", "2", "99.4", "2"),
+			newFakeExchange(t, "c", "100.4", "3", "99.6", "3"),
+		})
+		depth, err := consensus.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		require.NoError(t, depth.Validate())
+		assert.Equal(t, provider.ConsensusSource, depth.Source)
+		assert.Equal(t, []string{"a", "b", "c"}, depth.Venues)
+		assert.Equal(t, "100.2", depth.Asks[0].Price.String())
+		assert.Equal(t, "6", depth.Asks[0].Volume.String())
+		assert.Equal(t, "99.4", depth.Bids[0].Price.String())
+	})
+
+	t.Run("liquidity weighted", func(t *testing.T) {
+		consensus := provider.NewConsensus(zap.NewNop(), []provider.Named{
+			newFakeExchange(t, "a", "100", "1", "99", "3"),
+			newFakeExchange(t, "b", "101", "3", "98", "1"),
+		}, provider.WithAggregation(provider.AggregationWeighted))
+		depth, err := consensus.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, "100.75", depth.Asks[0].Price.String())
+		assert.Equal(t, "98.75", depth.Bids[0].Price.String())
+	})
+
+	t.Run("outlier and failed venues are excluded", func(t *testing.T) {
+		consensus := provider.NewConsensus(zap.NewNop(), []provider.Named{
+			newFakeExchange(t, "a", "100.0", "1", "99.0", "1"),
+			newFakeExchange(t, "b", "100.2", "1", "99.2", "1"),
+			newFakeExchange(t, "outlier", "120", "1", "119", "1"),
+			newBrokenExchange(t, "down"),
+		}, provider.WithMaxDeviationBps(50))
+		depth, err := consensus.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, depth.Venues)
+		assert.Equal(t, "100.1", depth.Asks[0].Price.String())
+	})
+
+	t.Run("too few venues", func(t *testing.T) {
+		consensus := provider.NewConsensus(zap.NewNop(), []provider.Named{
+			newFakeExchange(t, "a", "100", "1", "99", "1"),
+			newBrokenExchange(t, "down"),
+		}, provider.WithMinVenues(2))
+		_, err := consensus.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, models.ErrNoConsensus)
+	})
+
+	t.Run("all venues fail", func(t *testing.T) {
+		consensus := provider.NewConsensus(zap.NewNop(), []provider.Named{
+			newBrokenExchange(t, "down"),
+			newBrokenExchange(t, "also down"),
+		})
+		_, err := consensus.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, models.ErrAllProvidersFailed)
+	})
+}
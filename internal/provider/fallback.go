@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// Named is a depth provider with a name that is recorded as the source of its depth data.
+type Named struct {
+	Name     string
+	Provider service.DepthProvider
+}
+
+// Fallback is a DepthProvider that tries an ordered list of providers and returns
+// the depth data of the first one that answers with valid data.
+type Fallback struct {
+	logger      *zap.Logger
+	providers   []Named
+	marketOrder map[string][]Named
+}
+
+// FallbackOption configures optional Fallback settings.
+type FallbackOption func(*Fallback)
+
+// WithMarketOrder sets the providers to try for a specific market, in order.
+// It replaces the default order for that market.
+func WithMarketOrder(market string, providers ...Named) FallbackOption {
+	return func(f *Fallback) {
+		f.marketOrder[market] = providers
+	}
+}
+
+// NewFallback creates a new Fallback that tries the providers in the given order.
+func NewFallback(logger *zap.Logger, providers []Named, opts ...FallbackOption) *Fallback {
+	f := &Fallback{
+		logger:      logger,
+		providers:   providers,
+		marketOrder: make(map[string][]Named),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// GetDepth tries the providers configured for the market in order and returns the depth data
+// of the first one that returns valid data, with its name recorded as the source.
+// If every provider fails, the returned error wraps models.ErrAllProvidersFailed and the errors of all providers,
+// unless a single provider was tried: its error is then returned as is, so that its cause is not hidden.
+func (f *Fallback) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	providers, ok := f.marketOrder[market]
+	if !ok {
+		providers = f.providers
+	}
+
+	errs := make([]error, 0, len(providers))
+	for _, p := range providers {
+		depth, err := p.Provider.GetDepth(ctx, market)
+		if err == nil {
+			err = depth.Validate()
+		}
+		if err != nil {
+			f.logger.Warn("Depth provider failed, falling through",
+				zap.String("provider", p.Name),
+				zap.String("market", market),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		result := *depth
+		result.Source = p.Name
+		return &result, nil
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("%w: %w", models.ErrAllProvidersFailed, errors.Join(errs...))
+}
@@ -0,0 +1,104 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func dec(s string) models.Decimal {
+	return models.MustParseDecimal(s)
+}
+
+func validDepth(ask, bid string) *models.Depth {
+	return &models.Depth{
+		Asks:      []models.Order{{Price: dec(ask), Volume: dec("1"), Amount: dec(ask)}},
+		Bids:      []models.Order{{Price: dec(bid), Volume: dec("1"), Amount: dec(bid)}},
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func TestFallback_GetDepth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first provider answers", func(t *testing.T) {
+		primary := mocks.NewMockDepthProvider(t)
+		backup := mocks.NewMockDepthProvider(t)
+		primary.On("GetDepth", ctx, "usdtrub").Return(validDepth("100", "99"), nil)
+
+		fallback := provider.NewFallback(zap.NewNop(), []provider.Named{
+			{Name: "primary", Provider: primary},
+			{Name: "backup", Provider: backup},
+		})
+		depth, err := fallback.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, "primary", depth.Source)
+		backup.AssertNotCalled(t, "GetDepth")
+	})
+
+	t.Run("falls through on error and invalid depth", func(t *testing.T) {
+		broken := mocks.NewMockDepthProvider(t)
+		invalid := mocks.NewMockDepthProvider(t)
+		backup := mocks.NewMockDepthProvider(t)
+		broken.On("GetDepth", ctx, "usdtrub").Return(nil, errors.New("connection reset"))
+		invalid.On("GetDepth", ctx, "usdtrub").Return(validDepth("99", "100"), nil)
+		backup.On("GetDepth", ctx, "usdtrub").Return(validDepth("100", "99"), nil)
+
+		fallback := provider.NewFallback(zap.NewNop(), []provider.Named{
+			{Name: "broken", Provider: broken},
+			{Name: "invalid", Provider: invalid},
+			{Name: "backup", Provider: backup},
+		})
+		depth, err := fallback.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, "backup", depth.Source)
+	})
+
+	t.Run("all providers fail", func(t *testing.T) {
+		broken := mocks.NewMockDepthProvider(t)
+		invalid := mocks.NewMockDepthProvider(t)
+		broken.On("GetDepth", ctx, "usdtrub").Return(nil, errors.New("connection reset"))
+		invalid.On("GetDepth", ctx, "usdtrub").Return(validDepth("99", "100"), nil)
+
+		fallback := provider.NewFallback(zap.NewNop(), []provider.Named{
+			{Name: "broken", Provider: broken},
+			{Name: "invalid", Provider: invalid},
+		})
+		_, err := fallback.GetDepth(ctx, "usdtrub")
+		require.ErrorIs(t, err, models.ErrAllProvidersFailed)
+		require.ErrorIs(t, err, models.ErrCrossedBook)
+		assert.Contains(t, err.Error(), "broken: connection reset")
+	})
+
+	t.Run("single provider fails", func(t *testing.T) {
+		primary := mocks.NewMockDepthProvider(t)
+		primary.On("GetDepth", ctx, "usdtrbu").Return(nil, models.ErrUnknownMarket)
+
+		fallback := provider.NewFallback(zap.NewNop(), []provider.Named{{Name: "primary", Provider: primary}})
+		_, err := fallback.GetDepth(ctx, "usdtrbu")
+		require.ErrorIs(t, err, models.ErrUnknownMarket)
+		require.NotErrorIs(t, err, models.ErrAllProvidersFailed)
+		assert.Contains(t, err.Error(), "primary: ")
+	})
+
+	t.Run("market order", func(t *testing.T) {
+		primary := mocks.NewMockDepthProvider(t)
+		backup := mocks.NewMockDepthProvider(t)
+		backup.On("GetDepth", ctx, "btcusdt").Return(validDepth("100", "99"), nil)
+
+		named := []provider.Named{{Name: "primary", Provider: primary}, {Name: "backup", Provider: backup}}
+		fallback := provider.NewFallback(zap.NewNop(), named, provider.WithMarketOrder("btcusdt", named[1]))
+		depth, err := fallback.GetDepth(ctx, "btcusdt")
+		require.NoError(t, err)
+		assert.Equal(t, "backup", depth.Source)
+		primary.AssertNotCalled(t, "GetDepth")
+	})
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// minMarketsLoadInterval is the minimum time between two on-demand loads of the market list
+// while it has not been loaded yet, so that an outage does not turn every request into a list request.
+const minMarketsLoadInterval = 5 * time.Second
+
+// MarketRegistry keeps the markets listed by the exchange in memory and refreshes them periodically.
+// It implements service.MarketRegistry.
+type MarketRegistry struct {
+	logger          *zap.Logger
+	provider        service.MarketsProvider
+	refreshInterval time.Duration
+	// extra are the markets served by other providers, supported whether the exchange lists them or not.
+	extra map[string]struct{}
+
+	// loadMu serializes the loads of the list, mu guards the loaded list.
+	loadMu      sync.Mutex
+	lastAttempt time.Time
+	lastErr     error
+
+	mu      sync.RWMutex
+	markets []models.Market
+	known   map[string]struct{}
+}
+
+// MarketRegistryOption is a function that configures a MarketRegistry.
+type MarketRegistryOption func(*MarketRegistry)
+
+// WithExtraMarkets adds markets served by other providers, e.g. the markets mapped in a RESTConfig.
+// They are always supported and listed, with their identifier only if the exchange does not list them.
+func WithExtraMarkets(markets ...string) MarketRegistryOption {
+	return func(r *MarketRegistry) {
+		for _, market := range markets {
+			r.extra[market] = struct{}{}
+		}
+	}
+}
+
+// NewMarketRegistry creates a new MarketRegistry for the markets of the provider.
+// The list is loaded on first use and refreshed every refreshInterval while Run is running.
+func NewMarketRegistry(
+	logger *zap.Logger,
+	provider service.MarketsProvider,
+	refreshInterval time.Duration,
+	options ...MarketRegistryOption,
+) *MarketRegistry {
+	r := &MarketRegistry{
+		logger:          logger,
+		provider:        provider,
+		refreshInterval: refreshInterval,
+		extra:           make(map[string]struct{}),
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Run loads the market list and refreshes it every refresh interval until the context is canceled.
+// A failed refresh keeps the previous list.
+func (r *MarketRegistry) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.load(ctx, true); err != nil && ctx.Err() == nil {
+			r.logger.Warn("Failed to refresh the market list", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListMarkets returns the markets of the exchange followed by the extra markets,
+// loading them first if they have not been loaded yet.
+func (r *MarketRegistry) ListMarkets(ctx context.Context) ([]models.Market, error) {
+	if markets, ok := r.cached(); ok {
+		return markets, nil
+	}
+	if err := r.load(ctx, false); err != nil {
+		return nil, err
+	}
+	markets, _ := r.cached()
+	return markets, nil
+}
+
+// ValidateMarket returns an error wrapping models.ErrUnsupportedMarket if the exchange does not list the market
+// and it is not an extra market.
+// If the market list cannot be loaded, the market is accepted: an outage of the markets endpoint
+// must not block rates of markets the exchange still serves.
+func (r *MarketRegistry) ValidateMarket(ctx context.Context, market string) error {
+	if _, ok := r.extra[market]; ok {
+		return nil
+	}
+	if _, err := r.ListMarkets(ctx); err != nil {
+		r.logger.Warn("Market list unavailable, market not validated", zap.String("market", market), zap.Error(err))
+		return nil
+	}
+
+	r.mu.RLock()
+	_, ok := r.known[market]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", models.ErrUnsupportedMarket, market)
+	}
+	return nil
+}
+
+// cached returns the loaded market list, if any.
+func (r *MarketRegistry) cached() ([]models.Market, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.markets, r.known != nil
+}
+
+// load fetches the market list from the provider. Unless force is set, it does nothing if another call
+// has loaded the list in the meantime and returns the previous error if the last attempt was too recent.
+func (r *MarketRegistry) load(ctx context.Context, force bool) error {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	if !force {
+		if _, ok := r.cached(); ok {
+			return nil
+		}
+		if time.Since(r.lastAttempt) < minMarketsLoadInterval && r.lastErr != nil {
+			return r.lastErr
+		}
+	}
+
+	r.lastAttempt = time.Now()
+	markets, err := r.provider.ListMarkets(ctx)
+	r.lastErr = err
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]struct{}, len(markets)+len(r.extra))
+	for _, market := range markets {
+		known[market.ID] = struct{}{}
+	}
+	extra := make([]string, 0, len(r.extra))
+	for market := range r.extra {
+		if _, ok := known[market]; !ok {
+			extra = append(extra, market)
+			known[market] = struct{}{}
+		}
+	}
+	slices.Sort(extra)
+	markets = slices.Clip(markets)
+	for _, market := range extra {
+		markets = append(markets, models.Market{ID: market})
+	}
+	r.mu.Lock()
+	r.markets = markets
+	r.known = known
+	r.mu.Unlock()
+	r.logger.Debug("Market list loaded", zap.Int("markets", len(markets)))
+	return nil
+}
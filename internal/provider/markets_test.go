@@ -0,0 +1,86 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMarketRegistry(t *testing.T) {
+	ctx := context.Background()
+	markets := []models.Market{{ID: "usdtrub", Name: "USDT/RUB", Base: "usdt", Quote: "rub"}}
+
+	t.Run("loads on first use and validates", func(t *testing.T) {
+		upstream := mocks.NewMockMarketRegistry(t)
+		upstream.On("ListMarkets", ctx).Return(markets, nil).Once()
+
+		registry := provider.NewMarketRegistry(zap.NewNop(), upstream, time.Hour)
+		require.NoError(t, registry.ValidateMarket(ctx, "usdtrub"))
+		require.ErrorIs(t, registry.ValidateMarket(ctx, "usdtrbu"), models.ErrUnsupportedMarket)
+
+		listed, err := registry.ListMarkets(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, markets, listed)
+	})
+
+	t.Run("accepts any market while the list is unavailable", func(t *testing.T) {
+		upstream := mocks.NewMockMarketRegistry(t)
+		upstream.On("ListMarkets", ctx).Return(nil, models.ErrUpstreamUnavailable).Once()
+
+		registry := provider.NewMarketRegistry(zap.NewNop(), upstream, time.Hour)
+		require.NoError(t, registry.ValidateMarket(ctx, "usdtrub"))
+		require.NoError(t, registry.ValidateMarket(ctx, "anything"), "the failed load is not retried right away")
+
+		_, err := registry.ListMarkets(ctx)
+		require.ErrorIs(t, err, models.ErrUpstreamUnavailable)
+	})
+
+	t.Run("extra markets are supported and listed", func(t *testing.T) {
+		upstream := mocks.NewMockMarketRegistry(t)
+		upstream.On("ListMarkets", ctx).Return(markets, nil).Once()
+
+		registry := provider.NewMarketRegistry(zap.NewNop(), upstream, time.Hour,
+			provider.WithExtraMarkets("usdtrub", "usdtkzt"))
+		require.NoError(t, registry.ValidateMarket(ctx, "usdtkzt"))
+		require.ErrorIs(t, registry.ValidateMarket(ctx, "usdtrbu"), models.ErrUnsupportedMarket)
+
+		listed, err := registry.ListMarkets(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Market{markets[0], {ID: "usdtkzt"}}, listed)
+		assert.Len(t, markets, 1, "the list of the exchange is not modified")
+	})
+
+	t.Run("run refreshes the list and keeps it on failure", func(t *testing.T) {
+		refreshed := []models.Market{markets[0], {ID: "btcusdt"}}
+		upstream := mocks.NewMockMarketRegistry(t)
+		upstream.On("ListMarkets", mock.Anything).Return(markets, nil).Once()
+		upstream.On("ListMarkets", mock.Anything).Return(nil, errors.New("connection reset")).Once()
+		upstream.On("ListMarkets", mock.Anything).Return(refreshed, nil)
+
+		registry := provider.NewMarketRegistry(zap.NewNop(), upstream, 20*time.Millisecond)
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			registry.Run(runCtx)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return registry.ValidateMarket(ctx, "usdtrub") == nil
+		}, time.Second, 5*time.Millisecond)
+		assert.Eventually(t, func() bool {
+			return registry.ValidateMarket(ctx, "btcusdt") == nil
+		}, time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+	})
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// fixtureExt is the extension of the fixture files, one per market named after it, e.g. "usdtrub.jsonl".
+const fixtureExt = ".jsonl"
+
+// fixtureMarket matches the market names that can be used as fixture file names.
+var fixtureMarket = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// fixture is a recorded depth response, stored as one JSON line of the fixture file of its market.
+type fixture struct {
+	RecordedAt time.Time     `json:"recordedAt"`
+	Depth      *models.Depth `json:"depth"`
+}
+
+// Recorder is a DepthProvider decorator that appends every depth response of the wrapped provider
+// to the fixture file of its market in a directory, to be served later by Replay.
+// Errors are not recorded, and a failure to record is logged without failing the request.
+type Recorder struct {
+	logger   *zap.Logger
+	provider service.DepthProvider
+	dir      string
+
+	mu sync.Mutex
+}
+
+// NewRecorder wraps the provider in a Recorder writing to dir, which is created if it does not exist.
+func NewRecorder(logger *zap.Logger, provider service.DepthProvider, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create fixture directory: %w", err)
+	}
+	return &Recorder{
+		logger:   logger,
+		provider: provider,
+		dir:      dir,
+	}, nil
+}
+
+// GetDepth returns the depth data of the wrapped provider and records it.
+func (r *Recorder) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	depth, err := r.provider.GetDepth(ctx, market)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.record(market, depth); err != nil {
+		r.logger.Warn("Failed to record depth", zap.String("market", market), zap.Error(err))
+	}
+	return depth, nil
+}
+
+// record appends the depth data to the fixture file of the market.
+func (r *Recorder) record(market string, depth *models.Depth) error {
+	if !fixtureMarket.MatchString(market) {
+		return fmt.Errorf("market %q cannot be used as a file name", market)
+	}
+	line, err := json.Marshal(fixture{RecordedAt: time.Now(), Depth: depth})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	file, err := os.OpenFile(filepath.Join(r.dir, market+fixtureExt), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"usdt-rate-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// ReplaySource is the source name recorded in the depth data returned by Replay.
+const ReplaySource = "replay"
+
+// maxFixtureLine is the maximum size of a recorded depth response.
+const maxFixtureLine = 16 << 20
+
+// ReplayMode is the order in which Replay serves the recorded depth responses of a market.
+type ReplayMode string
+
+const (
+	// ReplaySequence serves the responses one after another, one per request, starting over after the last one.
+	ReplaySequence ReplayMode = "sequence"
+	// ReplayTimestamp serves the response recorded at the same time since the start of the recording
+	// as the time since the Replay was created, so that the data changes at the recorded pace.
+	// The last response is served for the average interval between the responses, then the recording starts over.
+	ReplayTimestamp ReplayMode = "timestamp"
+)
+
+// Replay is a DepthProvider that serves the depth responses recorded by Recorder, without any network access.
+// The served depth data is timestamped with the current time, so that it passes the data age checks.
+// It also implements service.MarketsProvider with the markets that have recordings.
+type Replay struct {
+	logger   *zap.Logger
+	mode     ReplayMode
+	start    time.Time
+	fixtures map[string][]fixture
+
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+// NewReplay loads the fixture files in dir and creates a Replay serving them in the given mode.
+func NewReplay(logger *zap.Logger, dir string, mode ReplayMode) (*Replay, error) {
+	if mode != ReplaySequence && mode != ReplayTimestamp {
+		return nil, fmt.Errorf("unknown replay mode %q", mode)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+fixtureExt))
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make(map[string][]fixture, len(paths))
+	for _, path := range paths {
+		market := strings.TrimSuffix(filepath.Base(path), fixtureExt)
+		loaded, err := loadFixtures(path)
+		if err != nil {
+			return nil, fmt.Errorf("load fixtures of %s: %w", market, err)
+		}
+		if len(loaded) > 0 {
+			fixtures[market] = loaded
+		}
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	logger.Info("Depth fixtures loaded", zap.String("dir", dir), zap.Int("markets", len(fixtures)))
+
+	return &Replay{
+		logger:   logger,
+		mode:     mode,
+		start:    time.Now(),
+		fixtures: fixtures,
+		cursors:  make(map[string]int, len(fixtures)),
+	}, nil
+}
+
+// GetDepth returns the next recorded depth data of the market according to the replay mode.
+// It returns an error wrapping models.ErrUnknownMarket if the market has no recordings.
+func (r *Replay) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fixtures, ok := r.fixtures[market]
+	if !ok {
+		return nil, fmt.Errorf("%w: no recorded depth for %s", models.ErrUnknownMarket, market)
+	}
+
+	var i int
+	if r.mode == ReplayTimestamp {
+		i = r.atElapsed(fixtures, time.Since(r.start))
+	} else {
+		r.mu.Lock()
+		i = r.cursors[market] % len(fixtures)
+		r.cursors[market] = i + 1
+		r.mu.Unlock()
+	}
+
+	depth := *fixtures[i].Depth
+	depth.Timestamp = time.Now().Unix()
+	depth.Source = ReplaySource
+	depth.Cached = false
+	depth.CacheAge = 0
+	return &depth, nil
+}
+
+// ListMarkets returns the markets that have recordings.
+func (r *Replay) ListMarkets(_ context.Context) ([]models.Market, error) {
+	markets := make([]models.Market, 0, len(r.fixtures))
+	for market := range r.fixtures {
+		markets = append(markets, models.Market{ID: market, Name: market})
+	}
+	slices.SortFunc(markets, func(a, b models.Market) int { return strings.Compare(a.ID, b.ID) })
+	return markets, nil
+}
+
+// atElapsed returns the index of the last fixture recorded at most elapsed after the first one,
+// with elapsed wrapped around the duration of the recording extended by the average interval between fixtures,
+// so that the last fixture is served as long as the others on average.
+func (r *Replay) atElapsed(fixtures []fixture, elapsed time.Duration) int {
+	first := fixtures[0].RecordedAt
+	span := fixtures[len(fixtures)-1].RecordedAt.Sub(first)
+	if span <= 0 {
+		return 0
+	}
+	offset := elapsed % (span + span/time.Duration(len(fixtures)-1))
+	return sort.Search(len(fixtures), func(i int) bool {
+		return fixtures[i].RecordedAt.Sub(first) > offset
+	}) - 1
+}
+
+// loadFixtures reads the fixtures of a fixture file, sorted by recording time.
+func loadFixtures(path string) ([]fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var fixtures []fixture
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxFixtureLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var f fixture
+		if err = json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if f.Depth == nil {
+			return nil, fmt.Errorf("line %d: no depth", line)
+		}
+		fixtures = append(fixtures, f)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(fixtures, func(a, b fixture) int { return a.RecordedAt.Compare(b.RecordedAt) })
+	return fixtures, nil
+}
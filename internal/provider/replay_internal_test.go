@@ -0,0 +1,38 @@
+//nolint:testpackage // This file needs to be in the same package because we are testing the package internals.
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay_atElapsed(t *testing.T) {
+	start := time.Date(2025, 1, 26, 14, 0, 0, 0, time.UTC)
+	fixtures := []fixture{
+		{RecordedAt: start},
+		{RecordedAt: start.Add(10 * time.Second)},
+		{RecordedAt: start.Add(30 * time.Second)},
+		{RecordedAt: start.Add(60 * time.Second)},
+	}
+
+	var r Replay
+	for elapsed, want := range map[time.Duration]int{
+		0:                0,
+		9 * time.Second:  0,
+		10 * time.Second: 1,
+		29 * time.Second: 1,
+		45 * time.Second: 2,
+		59 * time.Second: 2,
+		// The last fixture is served for the average interval of 20s, then the recording starts over.
+		60 * time.Second: 3,
+		79 * time.Second: 3,
+		80 * time.Second: 0,
+		95 * time.Second: 1,
+	} {
+		assert.Equal(t, want, r.atElapsed(fixtures, elapsed), "elapsed %s", elapsed)
+	}
+
+	assert.Equal(t, 0, r.atElapsed(fixtures[:1], time.Hour), "a single fixture")
+}
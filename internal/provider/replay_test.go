@@ -0,0 +1,123 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// record records the depth responses of usdtrub with a Recorder in a new directory and returns it.
+func record(t *testing.T, depths ...*models.Depth) string {
+	t.Helper()
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	upstream := mocks.NewMockDepthProvider(t)
+	for _, depth := range depths {
+		upstream.On("GetDepth", ctx, "usdtrub").Return(depth, nil).Once()
+	}
+
+	recorder, err := provider.NewRecorder(zap.NewNop(), upstream, dir)
+	require.NoError(t, err)
+	for _, depth := range depths {
+		recorded, err := recorder.GetDepth(ctx, "usdtrub")
+		require.NoError(t, err)
+		assert.Same(t, depth, recorded)
+	}
+	return dir
+}
+
+// depthJSON returns a recorded depth with the ask price.
+func depthJSON(ask string) string {
+	return `{"timestamp":1,"asks":[{"price":"` + ask + `","volume":"1","amount":"` + ask + `"}],` +
+		`"bids":[{"price":"99","volume":"1","amount":"99"}]}`
+}
+
+func TestRecorder_GetDepth(t *testing.T) {
+	ctx := context.Background()
+	errUpstream := errors.New("connection reset")
+	upstream := mocks.NewMockDepthProvider(t)
+	upstream.On("GetDepth", ctx, "usdtrub").Return(nil, errUpstream).Once()
+	upstream.On("GetDepth", ctx, "../etc").Return(validDepth("100", "99"), nil).Once()
+
+	dir := t.TempDir()
+	recorder, err := provider.NewRecorder(zap.NewNop(), upstream, dir)
+	require.NoError(t, err)
+
+	_, err = recorder.GetDepth(ctx, "usdtrub")
+	require.ErrorIs(t, err, errUpstream)
+	_, err = recorder.GetDepth(ctx, "../etc")
+	require.NoError(t, err, "a market that cannot be recorded is still served")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestReplay_GetDepth(t *testing.T) {
+	ctx := context.Background()
+	dir := record(t, validDepth("100", "99"), validDepth("101", "98"))
+
+	t.Run("sequence", func(t *testing.T) {
+		replay, err := provider.NewReplay(zap.NewNop(), dir, provider.ReplaySequence)
+		require.NoError(t, err)
+
+		var asks []string
+		for range 3 {
+			depth, err := replay.GetDepth(ctx, "usdtrub")
+			require.NoError(t, err)
+			require.NoError(t, depth.Validate())
+			assert.Equal(t, provider.ReplaySource, depth.Source)
+			asks = append(asks, depth.Asks[0].Price.String())
+		}
+		assert.Equal(t, []string{"100", "101", "100"}, asks)
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		timed := t.TempDir()
+		fixtures := `{"recordedAt":"2025-01-26T14:00:00Z","depth":` + depthJSON("100") + "}\n" +
+			`{"recordedAt":"2025-01-26T15:00:00Z","depth":` + depthJSON("101") + "}\n"
+		require.NoError(t, os.WriteFile(filepath.Join(timed, "usdtrub.jsonl"), []byte(fixtures), 0o600))
+		replay, err := provider.NewReplay(zap.NewNop(), timed, provider.ReplayTimestamp)
+		require.NoError(t, err)
+
+		for range 2 {
+			depth, err := replay.GetDepth(ctx, "usdtrub")
+			require.NoError(t, err)
+			assert.Equal(t, "100", depth.Asks[0].Price.String(), "the recording starts with the replay")
+			assert.InDelta(t, time.Now().Unix(), depth.Timestamp, 1)
+		}
+	})
+
+	t.Run("markets", func(t *testing.T) {
+		replay, err := provider.NewReplay(zap.NewNop(), dir, provider.ReplaySequence)
+		require.NoError(t, err)
+
+		_, err = replay.GetDepth(ctx, "btcusdt")
+		require.ErrorIs(t, err, models.ErrUnknownMarket)
+		markets, err := replay.ListMarkets(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Market{{ID: "usdtrub", Name: "usdtrub"}}, markets)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := provider.NewReplay(zap.NewNop(), dir, "random")
+		require.Error(t, err)
+		_, err = provider.NewReplay(zap.NewNop(), t.TempDir(), provider.ReplaySequence)
+		require.Error(t, err, "no fixtures")
+
+		broken := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(broken, "usdtrub.jsonl"), []byte("{"), 0o600))
+		_, err = provider.NewReplay(zap.NewNop(), broken, provider.ReplaySequence)
+		require.ErrorContains(t, err, "line 1")
+	})
+}
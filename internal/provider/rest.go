@@ -0,0 +1,303 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"usdt-rate-service/internal/models"
+)
+
+const (
+	// marketPlaceholder is replaced by the exchange market name in RESTConfig.URL.
+	marketPlaceholder = "{market}"
+	// defaultRESTTimeout is the request timeout of a REST provider without one configured.
+	defaultRESTTimeout = 10 * time.Second
+	// maxRESTBodySize is the size of the largest depth response read by a REST provider.
+	maxRESTBodySize = 10 << 20
+)
+
+// RESTConfig declares how to get the depth data of an exchange with a REST depth API.
+//
+// The paths locate values in the JSON response: keys separated by dots, with numbers indexing arrays,
+// e.g. "data.asks" or "0". The price and volume paths are relative to a level of the asks and bids arrays,
+// so that both [["80.5","10"]] levels ("0" and "1") and [{"price":"80.5","amount":"10"}] levels
+// ("price" and "amount") are supported. Numbers may be JSON strings or numbers.
+type RESTConfig struct {
+	// Name is the name of the provider in DEPTH_PROVIDERS and the source of its depth data.
+	Name string `json:"name"`
+	// URL is the depth endpoint, with {market} replaced by the exchange market name,
+	// e.g. "https://api.example.com/depth?symbol={market}".
+	URL string `json:"url"`
+	// Markets maps the markets of the service to the market names of the exchange, e.g. "usdtrub": "USDT_RUB".
+	// If set, other markets are unknown to the provider; if empty, the market names are used as is.
+	Markets map[string]string `json:"markets"`
+	// Headers are added to every request, e.g. an API key.
+	Headers map[string]string `json:"headers"`
+	// Timeout is the request timeout, e.g. "5s". It defaults to 10s.
+	Timeout string `json:"timeout"`
+
+	Asks   string `json:"asks"`
+	Bids   string `json:"bids"`
+	Price  string `json:"price"`
+	Volume string `json:"volume"`
+	// Timestamp is the path of the time of the depth data, as a unix time in TimestampUnit or an RFC 3339 string.
+	// If empty, the depth data is timestamped with the time it was received.
+	Timestamp string `json:"timestamp"`
+	// TimestampUnit is the unit of a numeric timestamp: "s" (the default), "ms", "us" or "ns".
+	TimestampUnit string `json:"timestampUnit"`
+}
+
+// REST is a DepthProvider for an exchange with a REST depth API, configured declaratively with a RESTConfig.
+// Its depth data goes through the same validation as the data of the other providers.
+type REST struct {
+	config     RESTConfig
+	httpClient *http.Client
+	unit       time.Duration
+}
+
+// NewREST creates a new REST provider. It returns an error if the config is incomplete.
+func NewREST(config RESTConfig) (*REST, error) {
+	switch {
+	case config.Name == "":
+		return nil, errors.New("REST provider without a name")
+	case !strings.Contains(config.URL, marketPlaceholder):
+		return nil, fmt.Errorf("REST provider %s: URL without %s", config.Name, marketPlaceholder)
+	case config.Asks == "" || config.Bids == "" || config.Price == "" || config.Volume == "":
+		return nil, fmt.Errorf("REST provider %s: asks, bids, price and volume paths are required", config.Name)
+	}
+
+	timeout := defaultRESTTimeout
+	if config.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(config.Timeout); err != nil {
+			return nil, fmt.Errorf("REST provider %s: invalid timeout: %w", config.Name, err)
+		}
+	}
+	units := map[string]time.Duration{
+		"": time.Second, "s": time.Second, "ms": time.Millisecond, "us": time.Microsecond, "ns": time.Nanosecond,
+	}
+	unit, ok := units[config.TimestampUnit]
+	if !ok {
+		return nil, fmt.Errorf("REST provider %s: unknown timestamp unit %q", config.Name, config.TimestampUnit)
+	}
+
+	return &REST{
+		config:     config,
+		httpClient: &http.Client{Timeout: timeout},
+		unit:       unit,
+	}, nil
+}
+
+// LoadRESTConfigs reads the REST provider configs from a JSON file holding an array of them.
+func LoadRESTConfigs(path string) ([]RESTConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var configs []RESTConfig
+	if err = json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return configs, nil
+}
+
+// GetDepth retrieves the depth data of the market from the exchange.
+// It returns an error wrapping models.ErrUnknownMarket if the market is not mapped or the exchange does not know it,
+// models.ErrInvalidDepth or models.ErrInvalidDecimal if the response does not match the config,
+// and models.ErrUpstreamTimeout or models.ErrUpstreamUnavailable if the request fails.
+func (r *REST) GetDepth(ctx context.Context, market string) (*models.Depth, error) {
+	symbol := market
+	if len(r.config.Markets) > 0 {
+		var ok bool
+		if symbol, ok = r.config.Markets[market]; !ok {
+			return nil, fmt.Errorf("%w: %s is not mapped for %s", models.ErrUnknownMarket, market, r.config.Name)
+		}
+	}
+
+	body, err := r.get(ctx, r.depthURL(symbol))
+	if err != nil {
+		return nil, err
+	}
+	var response any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err = decoder.Decode(&response); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidDepth, err)
+	}
+	return r.toDepth(response)
+}
+
+// depthURL returns the depth endpoint of the exchange market, escaping the market name for the path
+// or the query, depending on where the placeholder is.
+func (r *REST) depthURL(symbol string) string {
+	path, query, hasQuery := strings.Cut(r.config.URL, "?")
+	path = strings.ReplaceAll(path, marketPlaceholder, url.PathEscape(symbol))
+	if !hasQuery {
+		return path
+	}
+	return path + "?" + strings.ReplaceAll(query, marketPlaceholder, url.QueryEscape(symbol))
+}
+
+// get sends the request and returns the body of a 200 response.
+func (r *REST) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return nil, err
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return nil, fmt.Errorf("%w: %w", models.ErrUpstreamTimeout, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", models.ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s answered %s", models.ErrUnknownMarket, r.config.Name, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s answered %s", models.ErrUpstreamUnavailable, r.config.Name, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRESTBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrUpstreamUnavailable, err)
+	}
+	if len(body) > maxRESTBodySize {
+		return nil, fmt.Errorf("%w: response larger than %d bytes", models.ErrInvalidDepth, maxRESTBodySize)
+	}
+	return body, nil
+}
+
+// toDepth extracts the depth data from the decoded response according to the config.
+func (r *REST) toDepth(response any) (*models.Depth, error) {
+	asks, err := r.toOrders(response, r.config.Asks)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := r.toOrders(response, r.config.Bids)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	if r.config.Timestamp != "" {
+		if timestamp, err = r.toTimestamp(response); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Depth{
+		Timestamp: timestamp,
+		Asks:      asks,
+		Bids:      bids,
+		Source:    r.config.Name,
+	}, nil
+}
+
+// toOrders converts the levels of one side of the book. The amount of an order is derived from its price and volume.
+func (r *REST) toOrders(response any, path string) ([]models.Order, error) {
+	value, err := lookup(response, path)
+	if err != nil {
+		return nil, err
+	}
+	levels, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not an array", models.ErrInvalidDepth, path)
+	}
+
+	orders := make([]models.Order, len(levels))
+	for i, level := range levels {
+		price, err := lookupDecimal(level, r.config.Price)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d].%s: %w", path, i, r.config.Price, err)
+		}
+		volume, err := lookupDecimal(level, r.config.Volume)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d].%s: %w", path, i, r.config.Volume, err)
+		}
+		orders[i] = models.Order{Price: price, Volume: volume, Amount: price.Mul(volume)}
+	}
+	return orders, nil
+}
+
+// toTimestamp returns the time of the depth data in unix seconds.
+func (r *REST) toTimestamp(response any) (int64, error) {
+	value, err := lookup(response, r.config.Timestamp)
+	if err != nil {
+		return 0, err
+	}
+	text := scalarText(value)
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t.Unix(), nil
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %q", models.ErrInvalidTimestamp, r.config.Timestamp, text)
+	}
+	return int64(number * float64(r.unit) / float64(time.Second)), nil
+}
+
+// lookup returns the value at the path in a decoded JSON value. An empty path is the value itself.
+func lookup(value any, path string) (any, error) {
+	if path == "" {
+		return value, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return nil, fmt.Errorf("%w: %s not found", models.ErrInvalidDepth, path)
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("%w: %s not found", models.ErrInvalidDepth, path)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("%w: %s not found", models.ErrInvalidDepth, path)
+		}
+	}
+	return value, nil
+}
+
+// lookupDecimal returns the number at the path, given either as a JSON string or a number.
+func lookupDecimal(value any, path string) (models.Decimal, error) {
+	value, err := lookup(value, path)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	return models.ParseDecimal(scalarText(value))
+}
+
+// scalarText returns the text of a JSON string or number, or an empty string for other values.
+func scalarText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,204 @@
+package provider_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestREST creates a REST provider of the config against a local server answering with the handler.
+func newTestREST(t *testing.T, config provider.RESTConfig, handler http.HandlerFunc) *provider.REST {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config.URL = server.URL + config.URL
+	rest, err := provider.NewREST(config)
+	require.NoError(t, err)
+	return rest
+}
+
+func TestREST_GetDepth(t *testing.T) {
+	t.Run("array levels", func(t *testing.T) {
+		rest := newTestREST(t, provider.RESTConfig{
+			Name:          "example",
+			URL:           "/depth?symbol={market}",
+			Markets:       map[string]string{"usdtrub": "USDT_RUB"},
+			Headers:       map[string]string{"X-Api-Key": "secret"},
+			Asks:          "data.asks",
+			Bids:          "data.bids",
+			Price:         "0",
+			Volume:        "1",
+			Timestamp:     "data.ts",
+			TimestampUnit: "ms",
+		}, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "USDT_RUB", r.URL.Query().Get("symbol"))
+			assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+			_, _ = io.WriteString(w,
+				`{"data":{"ts":1737901234567,"asks":[["80.5","10"],[80.6,2]],"bids":[["80.1",1]]}}`)
+		})
+
+		depth, err := rest.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+		require.NoError(t, depth.Validate())
+		assert.Equal(t, int64(1737901234), depth.Timestamp)
+		assert.Equal(t, "example", depth.Source)
+		require.Len(t, depth.Asks, 2)
+		assert.Equal(t, "80.6", depth.Asks[1].Price.String())
+		assert.Equal(t, "805", depth.Asks[0].Amount.String())
+		assert.Equal(t, "80.1", depth.Bids[0].Price.String())
+
+		_, err = rest.GetDepth(context.Background(), "btcusdt")
+		require.ErrorIs(t, err, models.ErrUnknownMarket)
+	})
+
+	t.Run("object levels", func(t *testing.T) {
+		rest := newTestREST(t, provider.RESTConfig{
+			Name:      "example",
+			URL:       "/markets/{market}/depth",
+			Asks:      "asks",
+			Bids:      "bids",
+			Price:     "price",
+			Volume:    "amount",
+			Timestamp: "time",
+		}, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/markets/usdtrub/depth", r.URL.Path)
+			_, _ = io.WriteString(w, `{"time":"2025-01-26T14:20:34Z",`+
+				`"asks":[{"price":"80.5","amount":"10"}],"bids":[{"price":"80.1","amount":"1"}]}`)
+		})
+
+		depth, err := rest.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 1, 26, 14, 20, 34, 0, time.UTC).Unix(), depth.Timestamp)
+		assert.Equal(t, "10", depth.Asks[0].Volume.String())
+	})
+
+	t.Run("market escaped for the path and the query", func(t *testing.T) {
+		rest := newTestREST(t, provider.RESTConfig{
+			Name:    "example",
+			URL:     "/markets/{market}/depth?symbol={market}",
+			Markets: map[string]string{"usdtrub": "USDT RUB"},
+			Asks:    "a",
+			Bids:    "b",
+			Price:   "0",
+			Volume:  "1",
+		}, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/markets/USDT%20RUB/depth", r.URL.EscapedPath())
+			assert.Equal(t, "symbol=USDT+RUB", r.URL.RawQuery)
+			_, _ = io.WriteString(w, `{"a":[["80.5","10"]],"b":[["80.1","1"]]}`)
+		})
+
+		_, err := rest.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+	})
+
+	t.Run("without a timestamp", func(t *testing.T) {
+		rest := newTestREST(t, provider.RESTConfig{
+			Name: "example", URL: "/{market}", Asks: "a", Bids: "b", Price: "0", Volume: "1",
+		}, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, `{"a":[["80.5","10"]],"b":[["80.1","1"]]}`)
+		})
+
+		depth, err := rest.GetDepth(context.Background(), "usdtrub")
+		require.NoError(t, err)
+		assert.InDelta(t, time.Now().Unix(), depth.Timestamp, 1)
+	})
+}
+
+func TestREST_GetDepth_Errors(t *testing.T) {
+	config := provider.RESTConfig{
+		Name: "example", URL: "/{market}", Asks: "asks", Bids: "bids", Price: "0", Volume: "1", Timestamp: "ts",
+	}
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+		wantMsg string
+	}{
+		{name: "unknown market", status: http.StatusNotFound, wantErr: models.ErrUnknownMarket},
+		{name: "outage", status: http.StatusBadGateway, wantErr: models.ErrUpstreamUnavailable},
+		{name: "not JSON", body: `<html></html>`, wantErr: models.ErrInvalidDepth},
+		{name: "missing side", body: `{"asks":[],"ts":1}`, wantErr: models.ErrInvalidDepth, wantMsg: "bids not found"},
+		{name: "side is not an array", body: `{"asks":{},"bids":[],"ts":1}`, wantErr: models.ErrInvalidDepth},
+		{
+			name:    "invalid price",
+			body:    `{"asks":[["80.5","1"],["abc","1"]],"bids":[],"ts":1}`,
+			wantErr: models.ErrInvalidDecimal,
+			wantMsg: "asks[1].0",
+		},
+		{
+			name:    "missing volume",
+			body:    `{"asks":[["80.5"]],"bids":[],"ts":1}`,
+			wantErr: models.ErrInvalidDepth,
+			wantMsg: "asks[0].1",
+		},
+		{name: "invalid timestamp", body: `{"asks":[],"bids":[],"ts":"soon"}`, wantErr: models.ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest := newTestREST(t, config, func(w http.ResponseWriter, _ *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				_, _ = io.WriteString(w, tt.body)
+			})
+
+			_, err := rest.GetDepth(context.Background(), "usdtrub")
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestNewREST(t *testing.T) {
+	valid := provider.RESTConfig{Name: "example", URL: "/{market}", Asks: "a", Bids: "b", Price: "0", Volume: "1"}
+
+	_, err := provider.NewREST(valid)
+	require.NoError(t, err)
+
+	for name, modify := range map[string]func(*provider.RESTConfig){
+		"no name":              func(c *provider.RESTConfig) { c.Name = "" },
+		"no market in the URL": func(c *provider.RESTConfig) { c.URL = "/depth" },
+		"no price path":        func(c *provider.RESTConfig) { c.Price = "" },
+		"invalid timeout":      func(c *provider.RESTConfig) { c.Timeout = "soon" },
+		"unknown unit":         func(c *provider.RESTConfig) { c.TimestampUnit = "h" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			modify(&config)
+			_, err := provider.NewREST(config)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoadRESTConfigs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{
+		"name": "example",
+		"url": "https://api.example.com/depth?symbol={market}",
+		"markets": {"usdtrub": "USDT_RUB"},
+		"asks": "asks", "bids": "bids", "price": "0", "volume": "1",
+		"timestamp": "ts", "timestampUnit": "ms"
+	}]`), 0o600))
+
+	configs, err := provider.LoadRESTConfigs(path)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "USDT_RUB", configs[0].Markets["usdtrub"])
+	assert.Equal(t, "ms", configs[0].TimestampUnit)
+
+	_, err = provider.LoadRESTConfigs(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"usdt-rate-service/internal/models"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,12 +24,157 @@ func NewRates(pool *pgxpool.Pool) *Rates {
 }
 
 // SaveRate saves a Rate model to the database.
-// It inserts the market, ask price, bid price, and timestamp into the rates table.
+// It inserts the market, ask price, bid price, timestamp, calculation method,
+// mid price, spread and source into the rates table.
 func (r *Rates) SaveRate(ctx context.Context, rate *models.Rate) error {
 	query := `
-	  INSERT INTO rates (market, ask, bid, timestamp) 
-	  VALUES ($1, $2, $3, $4)
+	  INSERT INTO rates (market, ask, bid, timestamp, method, mid, spread, spread_bps, source) 
+	  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
-	_, err := r.pool.Exec(ctx, query, rate.Market, rate.AskPrice, rate.BidPrice, rate.Timestamp)
+	_, err := r.pool.Exec(ctx, query,
+		rate.Market, rate.AskPrice, rate.BidPrice, rate.Timestamp, rate.Method,
+		rate.MidPrice, rate.Spread, rate.SpreadBps, rate.Source,
+	)
 	return err
 }
+
+// GetRateHistory reads a page of the stored rates of a market, newest first, using keyset pagination
+// on (market, timestamp, id). Open bounds and the first page are queried with the extreme values,
+// so that every page is the same range scan of idx_rates_market_timestamp_id.
+// The cursor ID is bound as a bigint: the id column is an integer, which cannot hold the extreme value.
+func (r *Rates) GetRateHistory(ctx context.Context, query models.RateHistoryQuery) ([]models.StoredRate, error) {
+	to := query.To
+	if to == 0 {
+		to = math.MaxInt64
+	}
+	after := models.RateCursor{Timestamp: math.MaxInt64, ID: math.MaxInt64}
+	if query.After != nil {
+		after = *query.After
+	}
+
+	sql := `
+	  SELECT id, market, ask, bid, timestamp, method, mid, spread, spread_bps, source
+	  FROM rates
+	  WHERE market = $1 AND timestamp >= $2 AND timestamp < $3 AND (timestamp, id) < ($4, $5::bigint)
+	  ORDER BY timestamp DESC, id DESC
+	  LIMIT $6
+	`
+	rows, err := r.pool.Query(ctx, sql, query.Market, query.From, to, after.Timestamp, after.ID, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]models.StoredRate, 0, query.Limit)
+	for rows.Next() {
+		rate, err := scanStoredRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *rate)
+	}
+	return rates, rows.Err()
+}
+
+// GetRateAsOf reads the latest rate of a market and method stored at or before timestamp and not before notBefore.
+// Of several rates with the same timestamp, the last saved one is returned.
+// It returns an error wrapping models.ErrRateNotFound if there is no such rate.
+func (r *Rates) GetRateAsOf(
+	ctx context.Context,
+	market string,
+	method models.CalculationMethod,
+	timestamp, notBefore int64,
+) (*models.StoredRate, error) {
+	query := `
+	  SELECT id, market, ask, bid, timestamp, method, mid, spread, spread_bps, source
+	  FROM rates
+	  WHERE market = $1 AND timestamp <= $2 AND timestamp >= $3 AND method = $4
+	  ORDER BY timestamp DESC, id DESC
+	  LIMIT 1
+	`
+	rate, err := scanStoredRate(r.pool.QueryRow(ctx, query, market, timestamp, notBefore, method))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no %s rate of %s between %d and %d",
+			models.ErrRateNotFound, method, market, notBefore, timestamp)
+	}
+	return rate, err
+}
+
+// GetLatestRate reads the newest rate of a market and method stored.
+// It returns an error wrapping models.ErrRateNotFound if no such rate is stored.
+func (r *Rates) GetLatestRate(
+	ctx context.Context,
+	market string,
+	method models.CalculationMethod,
+) (*models.StoredRate, error) {
+	query := `
+	  SELECT id, market, ask, bid, timestamp, method, mid, spread, spread_bps, source
+	  FROM rates
+	  WHERE market = $1 AND method = $2
+	  ORDER BY timestamp DESC, id DESC
+	  LIMIT 1
+	`
+	rate, err := scanStoredRate(r.pool.QueryRow(ctx, query, market, method))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no %s rate of %s stored", models.ErrRateNotFound, method, market)
+	}
+	return rate, err
+}
+
+// GetCandles aggregates the stored rates of a market and method into candles of the query interval, oldest first.
+// Only the intervals with at least one rate are returned. Rates without a mid price, saved before the mid price
+// was stored and backfilled with zero, are left out. The open and close prices are those of the first
+// and last rates of the interval by timestamp and then by id, the same order as GetRateHistory.
+func (r *Rates) GetCandles(ctx context.Context, query models.CandleQuery) ([]models.Candle, error) {
+	sql := `
+	  SELECT
+	    bucket,
+	    (array_agg(ask ORDER BY timestamp, id))[1], MAX(ask), MIN(ask),
+	    (array_agg(ask ORDER BY timestamp DESC, id DESC))[1],
+	    (array_agg(bid ORDER BY timestamp, id))[1], MAX(bid), MIN(bid),
+	    (array_agg(bid ORDER BY timestamp DESC, id DESC))[1],
+	    (array_agg(mid ORDER BY timestamp, id))[1], MAX(mid), MIN(mid),
+	    (array_agg(mid ORDER BY timestamp DESC, id DESC))[1],
+	    COUNT(*)
+	  FROM (
+	    SELECT id, ask, bid, mid, timestamp, timestamp - timestamp % $2 AS bucket
+	    FROM rates
+	    WHERE market = $1 AND timestamp >= $3 AND timestamp < $4 AND method = $5 AND mid <> 0
+	  ) AS bucketed
+	  GROUP BY bucket
+	  ORDER BY bucket
+	`
+	rows, err := r.pool.Query(ctx, sql, query.Market, query.Interval, query.From, query.To, query.Method)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candles []models.Candle
+	for rows.Next() {
+		var c models.Candle
+		if err = rows.Scan(
+			&c.Timestamp,
+			&c.Ask.Open, &c.Ask.High, &c.Ask.Low, &c.Ask.Close,
+			&c.Bid.Open, &c.Bid.High, &c.Bid.Low, &c.Bid.Close,
+			&c.Mid.Open, &c.Mid.High, &c.Mid.Low, &c.Mid.Close,
+			&c.Count,
+		); err != nil {
+			return nil, err
+		}
+		candles = append(candles, c)
+	}
+	return candles, rows.Err()
+}
+
+// scanStoredRate scans a row of the rates columns selected by GetRateHistory, GetRateAsOf and GetLatestRate.
+func scanStoredRate(row pgx.Row) (*models.StoredRate, error) {
+	var rate models.StoredRate
+	if err := row.Scan(
+		&rate.ID, &rate.Market, &rate.AskPrice, &rate.BidPrice, &rate.Timestamp, &rate.Method,
+		&rate.MidPrice, &rate.Spread, &rate.SpreadBps, &rate.Source,
+	); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
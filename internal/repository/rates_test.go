@@ -0,0 +1,144 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/repository"
+	"usdt-rate-service/pkg/database"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseAddressEnv names the address of a migrated Postgres database for the repository tests.
+const testDatabaseAddressEnv = "TEST_DATABASE_ADDRESS"
+
+// newTestPool connects to the test database and returns a market name unique to the test,
+// whose rates are deleted when the test ends. The test is skipped without a test database.
+func newTestPool(t *testing.T) (*pgxpool.Pool, string) {
+	t.Helper()
+	address := os.Getenv(testDatabaseAddressEnv)
+	if address == "" {
+		t.Skipf("%s is not set", testDatabaseAddressEnv)
+	}
+
+	pool, err := database.NewPostgresPool(context.Background(), address)
+	require.NoError(t, err)
+	market := "test" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), "DELETE FROM rates WHERE market = $1", market)
+		assert.NoError(t, err)
+		pool.Close()
+	})
+	return pool, market
+}
+
+// saveRates saves rates of the market with the given timestamps and method.
+func saveRates(
+	t *testing.T,
+	rates *repository.Rates,
+	market string,
+	method models.CalculationMethod,
+	timestamps ...int64,
+) {
+	t.Helper()
+	for _, timestamp := range timestamps {
+		require.NoError(t, rates.SaveRate(context.Background(), &models.Rate{
+			Market:    market,
+			AskPrice:  models.MustParseDecimal("80.5"),
+			BidPrice:  models.MustParseDecimal("80.1"),
+			MidPrice:  models.MustParseDecimal("80.3"),
+			Spread:    models.MustParseDecimal("0.4"),
+			SpreadBps: models.MustParseDecimal("49.8"),
+			Timestamp: timestamp,
+			Method:    method,
+			Source:    "grinex",
+		}))
+	}
+}
+
+func TestRates_GetRateHistory(t *testing.T) {
+	ctx := context.Background()
+	pool, market := newTestPool(t)
+	rates := repository.NewRates(pool)
+	saveRates(t, rates, market, models.MethodTop, 100, 200, 200, 300)
+
+	first, err := rates.GetRateHistory(ctx, models.RateHistoryQuery{Market: market, Limit: 3})
+	require.NoError(t, err, "the first page binds the extreme cursor")
+	require.Len(t, first, 3)
+	assert.Equal(t, int64(300), first[0].Timestamp)
+	assert.Equal(t, int64(200), first[1].Timestamp)
+	assert.Greater(t, first[1].ID, first[2].ID, "rates with the same timestamp are ordered by ID")
+
+	last := first[2]
+	second, err := rates.GetRateHistory(ctx, models.RateHistoryQuery{
+		Market: market,
+		Limit:  3,
+		After:  &models.RateCursor{Timestamp: last.Timestamp, ID: last.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, int64(100), second[0].Timestamp)
+
+	bounded, err := rates.GetRateHistory(ctx, models.RateHistoryQuery{Market: market, From: 150, To: 300, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, bounded, 2)
+}
+
+func TestRates_GetCandles(t *testing.T) {
+	ctx := context.Background()
+	pool, market := newTestPool(t)
+	rates := repository.NewRates(pool)
+	saveRates(t, rates, market, models.MethodTop, 60, 90)
+	saveRates(t, rates, market, models.MethodVWAP, 100)
+	// A rate saved before the mid price was stored, as backfilled by the migration.
+	_, err := pool.Exec(ctx, `
+	  INSERT INTO rates (market, ask, bid, timestamp, method, mid, spread, spread_bps, source)
+	  VALUES ($1, 80.5, 80.1, 110, 'top', 0, 0, 0, '')
+	`, market)
+	require.NoError(t, err)
+
+	candles, err := rates.GetCandles(ctx, models.CandleQuery{
+		Market: market, Method: models.MethodTop, Interval: 60, From: 60, To: 120,
+	})
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, int64(2), candles[0].Count, "other methods and rates without a mid price are left out")
+	assert.True(t, models.MustParseDecimal("80.3").Equal(candles[0].Mid.Low), candles[0].Mid.Low.String())
+}
+
+func TestRates_GetRateAsOf(t *testing.T) {
+	ctx := context.Background()
+	pool, market := newTestPool(t)
+	rates := repository.NewRates(pool)
+	saveRates(t, rates, market, models.MethodTop, 100)
+	saveRates(t, rates, market, models.MethodVWAP, 200)
+
+	rate, err := rates.GetRateAsOf(ctx, market, models.MethodTop, 300, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), rate.Timestamp, "a later rate of another method is not returned")
+
+	_, err = rates.GetRateAsOf(ctx, market, models.MethodTop, 300, 150)
+	require.ErrorIs(t, err, models.ErrRateNotFound)
+}
+
+func TestRates_GetLatestRate(t *testing.T) {
+	ctx := context.Background()
+	pool, market := newTestPool(t)
+	rates := repository.NewRates(pool)
+
+	_, err := rates.GetLatestRate(ctx, market, models.MethodTop)
+	require.ErrorIs(t, err, models.ErrRateNotFound)
+
+	saveRates(t, rates, market, models.MethodTop, 100)
+	saveRates(t, rates, market, models.MethodVWAP, 200)
+	rate, err := rates.GetLatestRate(ctx, market, models.MethodTop)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), rate.Timestamp, "a newer rate of another method is not returned")
+	assert.Equal(t, models.MethodTop, rate.Method)
+}
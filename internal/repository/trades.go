@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"usdt-rate-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Trades is a repository for managing trades in the database.
+type Trades struct {
+	pool *pgxpool.Pool
+}
+
+// NewTrades creates a new Trades repository with the provided database connection pool.
+func NewTrades(pool *pgxpool.Pool) *Trades {
+	return &Trades{
+		pool: pool,
+	}
+}
+
+// SaveTrades saves the trades to the database in a single batch.
+// Trades that are already stored, identified by their source, market and exchange ID, are skipped.
+func (r *Trades) SaveTrades(ctx context.Context, trades []models.Trade) error {
+	query := `
+	  INSERT INTO trades (source, market, trade_id, price, volume, amount, side, timestamp)
+	  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	  ON CONFLICT (source, market, trade_id) DO NOTHING
+	`
+	batch := &pgx.Batch{}
+	for _, trade := range trades {
+		batch.Queue(query,
+			trade.Source, trade.Market, trade.ID, trade.Price, trade.Volume,
+			trade.Amount, trade.Side, trade.Timestamp,
+		)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"fmt"
+	"usdt-rate-service/internal/models"
+)
+
+// Quote holds the ask and bid prices calculated from the depth data.
+type Quote struct {
+	AskPrice models.Decimal
+	BidPrice models.Decimal
+	// AskPartial and BidPartial report that the side of the book
+	// did not hold enough volume to fill the requested target volume.
+	AskPartial bool
+	BidPartial bool
+}
+
+// RateCalculator is a strategy that calculates a quote from the depth data.
+type RateCalculator interface {
+	Calculate(depth *models.Depth) (*Quote, error)
+}
+
+// NewRateCalculator returns the RateCalculator for the calculation method in the parameters.
+// It returns ErrInvalidCalculationParams if the method is unknown or its parameters are invalid.
+func NewRateCalculator(params GetRatesParams) (RateCalculator, error) {
+	switch params.Method {
+	case models.MethodTop:
+		return topOfBook{}, nil
+	case models.MethodTopN:
+		if params.N < 1 {
+			return nil, fmt.Errorf("%w: level n must be at least 1", models.ErrInvalidCalculationParams)
+		}
+		return levelPrice{level: params.N}, nil
+	case models.MethodAvgNM:
+		if params.N < 1 || params.M < params.N {
+			return nil, fmt.Errorf("%w: levels must satisfy 1 <= n <= m", models.ErrInvalidCalculationParams)
+		}
+		return levelsAverage{from: params.N, to: params.M}, nil
+	case models.MethodVWAP:
+		if !params.TargetVolume.IsPositive() {
+			return nil, fmt.Errorf("%w: target volume must be positive", models.ErrInvalidCalculationParams)
+		}
+		return volumeWeighted{targetVolume: params.TargetVolume}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown method %q", models.ErrInvalidCalculationParams, params.Method)
+	}
+}
+
+// topOfBook uses the best ask and bid prices.
+type topOfBook struct{}
+
+// Calculate implements RateCalculator.
+func (topOfBook) Calculate(depth *models.Depth) (*Quote, error) {
+	return &Quote{
+		AskPrice: depth.Asks[0].Price,
+		BidPrice: depth.Bids[0].Price,
+	}, nil
+}
+
+// levelPrice uses the prices at the given 1-based level of the book.
+type levelPrice struct {
+	level int
+}
+
+// Calculate implements RateCalculator.
+func (c levelPrice) Calculate(depth *models.Depth) (*Quote, error) {
+	if err := checkLevels(depth, c.level); err != nil {
+		return nil, err
+	}
+	return &Quote{
+		AskPrice: depth.Asks[c.level-1].Price,
+		BidPrice: depth.Bids[c.level-1].Price,
+	}, nil
+}
+
+// levelsAverage uses the average prices of the 1-based levels from..to inclusive.
+type levelsAverage struct {
+	from int
+	to   int
+}
+
+// Calculate implements RateCalculator.
+func (c levelsAverage) Calculate(depth *models.Depth) (*Quote, error) {
+	if err := checkLevels(depth, c.to); err != nil {
+		return nil, err
+	}
+	return &Quote{
+		AskPrice: averagePrice(depth.Asks[c.from-1 : c.to]),
+		BidPrice: averagePrice(depth.Bids[c.from-1 : c.to]),
+	}, nil
+}
+
+// volumeWeighted walks both sides of the book until the target volume is filled.
+type volumeWeighted struct {
+	targetVolume models.Decimal
+}
+
+// Calculate implements RateCalculator.
+func (c volumeWeighted) Calculate(depth *models.Depth) (*Quote, error) {
+	ask, err := volumeWeightedPrice(depth.Asks, c.targetVolume)
+	if err != nil {
+		return nil, err
+	}
+	bid, err := volumeWeightedPrice(depth.Bids, c.targetVolume)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{
+		AskPrice:   ask.Price,
+		BidPrice:   bid.Price,
+		AskPartial: ask.Partial,
+		BidPartial: bid.Partial,
+	}, nil
+}
+
+// basisPoints is the number of basis points in one.
+const basisPoints = 10000
+
+// setSpread fills the mid price, the spread and the spread in basis points of the rate.
+// It returns ErrCrossedBook if the bid price is not below the ask price.
+func setSpread(rate *models.Rate) error {
+	if rate.BidPrice.Cmp(rate.AskPrice) >= 0 {
+		return fmt.Errorf("%w: bid %s, ask %s", models.ErrCrossedBook, rate.BidPrice, rate.AskPrice)
+	}
+	rate.MidPrice = rate.AskPrice.Add(rate.BidPrice).Div(models.NewDecimalFromInt(2))
+	rate.Spread = rate.AskPrice.Sub(rate.BidPrice)
+	rate.SpreadBps = rate.Spread.Mul(models.NewDecimalFromInt(basisPoints)).Div(rate.MidPrice)
+	return nil
+}
+
+// checkLevels checks that both sides of the book have at least the given number of levels.
+func checkLevels(depth *models.Depth, level int) error {
+	if level > len(depth.Asks) || level > len(depth.Bids) {
+		return fmt.Errorf("%w: level %d requested, book has %d asks and %d bids",
+			models.ErrDepthLevelOutOfRange, level, len(depth.Asks), len(depth.Bids))
+	}
+	return nil
+}
+
+// averagePrice returns the arithmetic mean of the order prices.
+func averagePrice(orders []models.Order) models.Decimal {
+	var sum models.Decimal
+	for _, order := range orders {
+		sum = sum.Add(order.Price)
+	}
+	return sum.Div(models.NewDecimalFromInt(int64(len(orders))))
+}
+
+// fillResult is the outcome of walking one side of the order book.
+type fillResult struct {
+	// Price is the volume-weighted average price of the filled volume.
+	Price models.Decimal
+	// Partial is true when the side ran out of levels before the target volume was filled.
+	Partial bool
+}
+
+// volumeWeightedPrice walks the orders from the best level down until the target volume is filled
+// and returns the volume-weighted average price of the filled volume.
+// If the orders cannot fill the target volume, the price of everything available is returned
+// and the result is marked as partial.
+func volumeWeightedPrice(orders []models.Order, targetVolume models.Decimal) (*fillResult, error) {
+	var (
+		filled   models.Decimal
+		notional models.Decimal
+	)
+	for _, order := range orders {
+		take := order.Volume.Min(targetVolume.Sub(filled))
+		filled = filled.Add(take)
+		notional = notional.Add(take.Mul(order.Price))
+		if filled.Cmp(targetVolume) >= 0 {
+			break
+		}
+	}
+	if !filled.IsPositive() {
+		return nil, models.ErrIncompleteDepthData
+	}
+
+	return &fillResult{
+		Price:   notional.Div(filled),
+		Partial: filled.Cmp(targetVolume) < 0,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateCalculator_Calculate(t *testing.T) {
+	depth := &models.Depth{
+		Asks: []models.Order{
+			{Price: dec("100"), Volume: dec("10"), Amount: dec("1000")},
+			{Price: dec("101"), Volume: dec("10"), Amount: dec("1010")},
+			{Price: dec("105"), Volume: dec("10"), Amount: dec("1050")},
+		},
+		Bids: []models.Order{
+			{Price: dec("99"), Volume: dec("10"), Amount: dec("990")},
+			{Price: dec("97"), Volume: dec("10"), Amount: dec("970")},
+			{Price: dec("96"), Volume: dec("10"), Amount: dec("960")},
+		},
+		Timestamp: time.Now().Unix(),
+	}
+
+	tests := []struct {
+		name    string
+		params  service.GetRatesParams
+		wantAsk string
+		wantBid string
+		wantErr error
+	}{
+		{
+			name:    "top",
+			params:  service.GetRatesParams{Method: models.MethodTop},
+			wantAsk: "100",
+			wantBid: "99",
+		},
+		{
+			name:    "topN",
+			params:  service.GetRatesParams{Method: models.MethodTopN, N: 2},
+			wantAsk: "101",
+			wantBid: "97",
+		},
+		{
+			name:    "topN out of range",
+			params:  service.GetRatesParams{Method: models.MethodTopN, N: 4},
+			wantErr: models.ErrDepthLevelOutOfRange,
+		},
+		{
+			name:    "topN zero level",
+			params:  service.GetRatesParams{Method: models.MethodTopN},
+			wantErr: models.ErrInvalidCalculationParams,
+		},
+		{
+			name:    "avgNM",
+			params:  service.GetRatesParams{Method: models.MethodAvgNM, N: 2, M: 3},
+			wantAsk: "103",
+			wantBid: "96.5",
+		},
+		{
+			name:    "avgNM single level",
+			params:  service.GetRatesParams{Method: models.MethodAvgNM, N: 1, M: 1},
+			wantAsk: "100",
+			wantBid: "99",
+		},
+		{
+			name:    "avgNM out of range",
+			params:  service.GetRatesParams{Method: models.MethodAvgNM, N: 1, M: 5},
+			wantErr: models.ErrDepthLevelOutOfRange,
+		},
+		{
+			name:    "avgNM reversed levels",
+			params:  service.GetRatesParams{Method: models.MethodAvgNM, N: 3, M: 2},
+			wantErr: models.ErrInvalidCalculationParams,
+		},
+		{
+			name:    "vwap",
+			params:  service.GetRatesParams{Method: models.MethodVWAP, TargetVolume: dec("20")},
+			wantAsk: "100.5",
+			wantBid: "98",
+		},
+		{
+			name:    "vwap without volume",
+			params:  service.GetRatesParams{Method: models.MethodVWAP},
+			wantErr: models.ErrInvalidCalculationParams,
+		},
+		{
+			name:    "unknown method",
+			params:  service.GetRatesParams{Method: "median"},
+			wantErr: models.ErrInvalidCalculationParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calculator, err := service.NewRateCalculator(tt.params)
+			if err == nil {
+				var quote *service.Quote
+				quote, err = calculator.Calculate(depth)
+				if tt.wantErr == nil {
+					require.NoError(t, err)
+					assert.Equal(t, tt.wantAsk, quote.AskPrice.String())
+					assert.Equal(t, tt.wantBid, quote.BidPrice.String())
+					return
+				}
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
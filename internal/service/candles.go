@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"usdt-rate-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// MaxCandles is the maximum number of candles returned by GetCandles.
+const MaxCandles = 1000
+
+// GetCandlesParams are the parameters of GetCandles.
+type GetCandlesParams struct {
+	Market string
+	// Method is the calculation method of the aggregated rates, MethodTop if empty,
+	// so that a candle does not mix prices of different depths of the book.
+	Method models.CalculationMethod
+	// Interval is the length of a candle: "1m", "5m", "1h" or "1d".
+	Interval string
+	// From and To bound the time range in Unix seconds, From inclusive and To exclusive.
+	// They are widened to whole intervals. From is required, a zero To means now.
+	From int64
+	To   int64
+	// FillGaps returns the intervals without rates as candles with every price at the previous close
+	// and a zero count, instead of skipping them. Intervals before the first rate of the range are still skipped.
+	FillGaps bool
+}
+
+// GetCandles aggregates the rates of one calculation method stored for a market into candles of ask, bid
+// and mid prices, oldest first.
+// It returns an error wrapping models.ErrInvalidHistoryQuery if the interval or the time range is invalid,
+// or if the range spans more than MaxCandles intervals.
+func (s *RatesService) GetCandles(ctx context.Context, params GetCandlesParams) ([]models.Candle, error) {
+	logger := s.logger.With(
+		zap.String("service", "RatesService"),
+		zap.String("method", "GetCandles"),
+		zap.String("market", params.Market),
+	)
+
+	interval, err := models.ParseCandleInterval(params.Interval)
+	if err != nil {
+		return nil, err
+	}
+	to := params.To
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if params.From <= 0 || to <= params.From {
+		return nil, fmt.Errorf("%w: time range [%d, %d)", models.ErrInvalidHistoryQuery, params.From, params.To)
+	}
+	from := params.From - params.From%interval
+	to = (to + interval - 1) / interval * interval
+	if count := (to - from) / interval; count > MaxCandles {
+		return nil, fmt.Errorf("%w: %d candles requested, at most %d", models.ErrInvalidHistoryQuery, count, MaxCandles)
+	}
+	method := params.Method
+	if method == "" {
+		method = models.MethodTop
+	}
+
+	candles, err := s.ratesRepository.GetCandles(ctx, models.CandleQuery{
+		Market:   params.Market,
+		Method:   method,
+		Interval: interval,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		logger.Error("Failed to aggregate candles", zap.Error(err))
+		return nil, err
+	}
+	if params.FillGaps {
+		candles = fillGaps(candles, interval, to)
+	}
+	return candles, nil
+}
+
+// fillGaps inserts a candle for every empty interval between the first candle and to,
+// with every price at the close of the previous candle.
+func fillGaps(candles []models.Candle, interval, to int64) []models.Candle {
+	if len(candles) == 0 {
+		return candles
+	}
+	filled := make([]models.Candle, 0, (to-candles[0].Timestamp)/interval)
+	for i, candle := range candles {
+		if i > 0 {
+			filled = appendFlat(filled, candle.Timestamp, interval)
+		}
+		filled = append(filled, candle)
+	}
+	return appendFlat(filled, to, interval)
+}
+
+// appendFlat appends flat candles at the close of the last candle up to the interval starting at end, excluded.
+func appendFlat(candles []models.Candle, end, interval int64) []models.Candle {
+	last := candles[len(candles)-1]
+	flat := func(price models.OHLC) models.OHLC {
+		return models.OHLC{Open: price.Close, High: price.Close, Low: price.Close, Close: price.Close}
+	}
+	for ts := last.Timestamp + interval; ts < end; ts += interval {
+		candles = append(candles, models.Candle{
+			Timestamp: ts,
+			Ask:       flat(last.Ask),
+			Bid:       flat(last.Bid),
+			Mid:       flat(last.Mid),
+		})
+	}
+	return candles
+}
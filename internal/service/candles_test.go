@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// candle returns a candle of the interval starting at timestamp with every price moving from open to close.
+func candle(timestamp int64, open, closePrice string, count int64) models.Candle {
+	prices := models.OHLC{Open: dec(open), High: dec(closePrice), Low: dec(open), Close: dec(closePrice)}
+	return models.Candle{Timestamp: timestamp, Ask: prices, Bid: prices, Mid: prices, Count: count}
+}
+
+func TestRatesService_GetCandles(t *testing.T) {
+	ctx := context.Background()
+	// The range is widened to [60, 360), five one-minute intervals.
+	query := models.CandleQuery{Market: "usdtrub", Method: models.MethodTop, Interval: 60, From: 60, To: 360}
+	stored := []models.Candle{candle(120, "80", "81", 3), candle(240, "82", "83", 1)}
+
+	t.Run("skips empty intervals", func(t *testing.T) {
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetCandles", ctx, query).Return(stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		candles, err := svc.GetCandles(ctx, service.GetCandlesParams{
+			Market: "usdtrub", Interval: "1m", From: 90, To: 330,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, stored, candles)
+	})
+
+	t.Run("fills empty intervals with the previous close", func(t *testing.T) {
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetCandles", ctx, query).Return(stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		candles, err := svc.GetCandles(ctx, service.GetCandlesParams{
+			Market: "usdtrub", Interval: "1m", From: 90, To: 330, FillGaps: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Candle{
+			stored[0],
+			candle(180, "81", "81", 0),
+			stored[1],
+			candle(300, "83", "83", 0),
+		}, candles)
+	})
+
+	t.Run("aggregates the rates of the method", func(t *testing.T) {
+		vwap := query
+		vwap.Method = models.MethodVWAP
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetCandles", ctx, vwap).Return(stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		candles, err := svc.GetCandles(ctx, service.GetCandlesParams{
+			Market: "usdtrub", Method: models.MethodVWAP, Interval: "1m", From: 90, To: 330,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, stored, candles)
+	})
+
+	t.Run("no rates", func(t *testing.T) {
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetCandles", ctx, query).Return(nil, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		candles, err := svc.GetCandles(ctx, service.GetCandlesParams{
+			Market: "usdtrub", Interval: "1m", From: 60, To: 360, FillGaps: true,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, candles)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		svc := service.NewRatesService(zap.NewNop(), nil, mocks.NewMockRatesRepository(t))
+		for name, params := range map[string]service.GetCandlesParams{
+			"unknown interval": {Market: "usdtrub", Interval: "2m", From: 60, To: 120},
+			"no start":         {Market: "usdtrub", Interval: "1m", To: 120},
+			"empty range":      {Market: "usdtrub", Interval: "1m", From: 120, To: 120},
+			"too many candles": {Market: "usdtrub", Interval: "1m", From: 60, To: 60 * (service.MaxCandles + 2)},
+		} {
+			_, err := svc.GetCandles(ctx, params)
+			require.ErrorIs(t, err, models.ErrInvalidHistoryQuery, name)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"usdt-rate-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRateHistoryLimit is the number of rates returned by GetRateHistory when no limit is given.
+	DefaultRateHistoryLimit = 100
+	// MaxRateHistoryLimit is the maximum number of rates returned by GetRateHistory.
+	MaxRateHistoryLimit = 1000
+)
+
+// GetRateHistoryParams are the parameters of GetRateHistory.
+type GetRateHistoryParams struct {
+	Market string
+	// From and To bound the timestamps of the rates in Unix seconds, From inclusive and To exclusive.
+	// Zero leaves a side open.
+	From int64
+	To   int64
+	// Limit is the page size. Zero or less means DefaultRateHistoryLimit,
+	// larger limits are capped at MaxRateHistoryLimit.
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page.
+	Cursor string
+}
+
+// GetRateHistory reads a page of the rates stored for a market, newest first.
+// It returns an error wrapping models.ErrInvalidHistoryQuery if the time range or the cursor is invalid.
+func (s *RatesService) GetRateHistory(ctx context.Context, params GetRateHistoryParams) (*models.RateHistory, error) {
+	logger := s.logger.With(
+		zap.String("service", "RatesService"),
+		zap.String("method", "GetRateHistory"),
+		zap.String("market", params.Market),
+	)
+
+	if params.From < 0 || params.To < 0 || (params.To > 0 && params.From >= params.To) {
+		return nil, fmt.Errorf("%w: time range [%d, %d)", models.ErrInvalidHistoryQuery, params.From, params.To)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultRateHistoryLimit
+	}
+	limit = min(limit, MaxRateHistoryLimit)
+
+	query := models.RateHistoryQuery{
+		Market: params.Market,
+		From:   params.From,
+		To:     params.To,
+		// One more rate tells whether there is a next page.
+		Limit: limit + 1,
+	}
+	if params.Cursor != "" {
+		cursor, err := models.ParseRateCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = &cursor
+	}
+
+	rates, err := s.ratesRepository.GetRateHistory(ctx, query)
+	if err != nil {
+		logger.Error("Failed to read the rate history", zap.Error(err))
+		return nil, err
+	}
+
+	history := &models.RateHistory{Market: params.Market, Rates: rates}
+	if len(rates) > limit {
+		history.Rates = rates[:limit]
+		last := history.Rates[limit-1]
+		history.NextCursor = models.RateCursor{Timestamp: last.Timestamp, ID: last.ID}.String()
+	}
+	return history, nil
+}
+
+// GetRateAsOf returns the latest rate of a calculation method stored for a market at or before timestamp,
+// in Unix seconds, so that the stored history can answer which rate applied at a point in time.
+// An empty method means MethodTop, so that the rate is not one of another depth of the book saved later.
+// It returns an error wrapping models.ErrRateNotFound if no rate was stored within the maximum lookback
+// before timestamp, see WithRateAsOfMaxLookback, and models.ErrInvalidHistoryQuery if timestamp is not positive.
+func (s *RatesService) GetRateAsOf(
+	ctx context.Context,
+	market string,
+	method models.CalculationMethod,
+	timestamp int64,
+) (*models.StoredRate, error) {
+	if method == "" {
+		method = models.MethodTop
+	}
+	logger := s.logger.With(
+		zap.String("service", "RatesService"),
+		zap.String("method", "GetRateAsOf"),
+		zap.String("market", market),
+		zap.Int64("timestamp", timestamp),
+	)
+
+	if timestamp <= 0 {
+		return nil, fmt.Errorf("%w: timestamp %d", models.ErrInvalidHistoryQuery, timestamp)
+	}
+	var notBefore int64
+	if lookback := int64(s.rateAsOfMaxLookback / time.Second); lookback > 0 {
+		notBefore = max(timestamp-lookback, 0)
+	}
+
+	rate, err := s.ratesRepository.GetRateAsOf(ctx, market, method, timestamp, notBefore)
+	if err != nil {
+		if !errors.Is(err, models.ErrRateNotFound) {
+			logger.Error("Failed to read the rate", zap.Error(err))
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+// GetLatestRate returns the newest MethodTop rate stored for a market if it is at most maxAge old,
+// without calling the exchange. A zero maxAge accepts a stored rate of any age, however old.
+// Otherwise, or if no rate is stored or the stored rates cannot be read, the rate is fetched live and saved
+// as by GetRates with MethodTop, so that both paths return the same quantity.
+func (s *RatesService) GetLatestRate(
+	ctx context.Context,
+	market string,
+	maxAge time.Duration,
+) (*models.LatestRate, error) {
+	logger := s.logger.With(
+		zap.String("service", "RatesService"),
+		zap.String("method", "GetLatestRate"),
+		zap.String("market", market),
+	)
+
+	stored, err := s.ratesRepository.GetLatestRate(ctx, market, models.MethodTop)
+	switch {
+	case errors.Is(err, models.ErrRateNotFound):
+		logger.Debug("No stored rate, fetching it live")
+	case err != nil:
+		logger.Warn("Failed to read the latest stored rate, fetching it live", zap.Error(err))
+	default:
+		age := rateAge(stored.Timestamp)
+		if maxAge <= 0 || age <= maxAge {
+			return &models.LatestRate{Rate: stored.Rate, Stored: true, Age: age}, nil
+		}
+		logger.Debug("Stored rate is too old, fetching it live",
+			zap.Duration("age", age), zap.Duration("maxAge", maxAge))
+	}
+
+	rate, err := s.GetRates(ctx, GetRatesParams{Market: market, Method: models.MethodTop})
+	if err != nil {
+		return nil, err
+	}
+	return &models.LatestRate{Rate: *rate, Age: rateAge(rate.Timestamp)}, nil
+}
+
+// rateAge returns the time since a rate timestamp in Unix seconds, zero for timestamps in the future.
+func rateAge(timestamp int64) time.Duration {
+	return max(time.Since(time.Unix(timestamp, 0)), 0)
+}
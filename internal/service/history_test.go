@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func storedRate(id, timestamp int64) models.StoredRate {
+	return models.StoredRate{
+		ID:   id,
+		Rate: models.Rate{Market: "usdtrub", AskPrice: dec("80.5"), BidPrice: dec("80.1"), Timestamp: timestamp},
+	}
+}
+
+func TestRatesService_GetRateHistory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pages through the history", func(t *testing.T) {
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateHistory", ctx, models.RateHistoryQuery{Market: "usdtrub", From: 100, To: 300, Limit: 3}).
+			Return([]models.StoredRate{storedRate(5, 250), storedRate(4, 200), storedRate(3, 200)}, nil)
+		repo.On("GetRateHistory", ctx, models.RateHistoryQuery{
+			Market: "usdtrub", From: 100, To: 300, Limit: 3, After: &models.RateCursor{Timestamp: 200, ID: 4},
+		}).Return([]models.StoredRate{storedRate(3, 200)}, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		params := service.GetRateHistoryParams{Market: "usdtrub", From: 100, To: 300, Limit: 2}
+		history, err := svc.GetRateHistory(ctx, params)
+		require.NoError(t, err)
+		require.Len(t, history.Rates, 2)
+		assert.Equal(t, int64(4), history.Rates[1].ID)
+		require.NotEmpty(t, history.NextCursor)
+
+		params.Cursor = history.NextCursor
+		history, err = svc.GetRateHistory(ctx, params)
+		require.NoError(t, err)
+		require.Len(t, history.Rates, 1)
+		assert.Equal(t, int64(3), history.Rates[0].ID)
+		assert.Empty(t, history.NextCursor, "last page")
+	})
+
+	t.Run("default and maximum limits", func(t *testing.T) {
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateHistory", ctx, models.RateHistoryQuery{
+			Market: "usdtrub", Limit: service.DefaultRateHistoryLimit + 1,
+		}).
+			Return(nil, nil).Once()
+		repo.On("GetRateHistory", ctx, models.RateHistoryQuery{
+			Market: "usdtrub", Limit: service.MaxRateHistoryLimit + 1,
+		}).
+			Return(nil, nil).Once()
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		history, err := svc.GetRateHistory(ctx, service.GetRateHistoryParams{Market: "usdtrub"})
+		require.NoError(t, err)
+		assert.Empty(t, history.Rates)
+		_, err = svc.GetRateHistory(ctx, service.GetRateHistoryParams{Market: "usdtrub", Limit: 5000})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		svc := service.NewRatesService(zap.NewNop(), nil, mocks.NewMockRatesRepository(t))
+		for name, params := range map[string]service.GetRateHistoryParams{
+			"empty range":    {Market: "usdtrub", From: 200, To: 200},
+			"negative from":  {Market: "usdtrub", From: -1},
+			"invalid cursor": {Market: "usdtrub", Cursor: "not a cursor"},
+		} {
+			_, err := svc.GetRateHistory(ctx, params)
+			require.ErrorIs(t, err, models.ErrInvalidHistoryQuery, name)
+		}
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		errDB := errors.New("db down")
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateHistory", ctx, models.RateHistoryQuery{
+			Market: "usdtrub", Limit: service.DefaultRateHistoryLimit + 1,
+		}).
+			Return(nil, errDB)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		_, err := svc.GetRateHistory(ctx, service.GetRateHistoryParams{Market: "usdtrub"})
+		require.ErrorIs(t, err, errDB)
+	})
+}
+
+func TestRatesService_GetRateAsOf(t *testing.T) {
+	ctx := context.Background()
+	// 2026-03-31 23:59:59 MSK.
+	asOf := time.Date(2026, 3, 31, 23, 59, 59, 0, time.FixedZone("MSK", 3*60*60)).Unix()
+
+	t.Run("within the lookback", func(t *testing.T) {
+		stored := storedRate(7, asOf-30)
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateAsOf", ctx, "usdtrub", models.MethodTop, asOf, asOf-3600).Return(&stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo, service.WithRateAsOfMaxLookback(time.Hour))
+
+		rate, err := svc.GetRateAsOf(ctx, "usdtrub", "", asOf)
+		require.NoError(t, err)
+		assert.Equal(t, &stored, rate)
+	})
+
+	t.Run("rate of the method", func(t *testing.T) {
+		stored := storedRate(8, asOf-30)
+		stored.Method = models.MethodVWAP
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateAsOf", ctx, "usdtrub", models.MethodVWAP, asOf, int64(0)).Return(&stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		rate, err := svc.GetRateAsOf(ctx, "usdtrub", models.MethodVWAP, asOf)
+		require.NoError(t, err)
+		assert.Equal(t, &stored, rate)
+	})
+
+	t.Run("without a lookback", func(t *testing.T) {
+		notFound := fmt.Errorf("%w: usdtrub", models.ErrRateNotFound)
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetRateAsOf", ctx, "usdtrub", models.MethodTop, asOf, int64(0)).Return(nil, notFound)
+		svc := service.NewRatesService(zap.NewNop(), nil, repo)
+
+		_, err := svc.GetRateAsOf(ctx, "usdtrub", models.MethodTop, asOf)
+		require.ErrorIs(t, err, models.ErrRateNotFound)
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		svc := service.NewRatesService(zap.NewNop(), nil, mocks.NewMockRatesRepository(t))
+		_, err := svc.GetRateAsOf(ctx, "usdtrub", "", 0)
+		require.ErrorIs(t, err, models.ErrInvalidHistoryQuery)
+	})
+}
+
+func TestRatesService_GetLatestRate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	depth := &models.Depth{
+		Asks:      []models.Order{{Price: dec("80.5"), Volume: dec("1"), Amount: dec("80.5")}},
+		Bids:      []models.Order{{Price: dec("80.1"), Volume: dec("1"), Amount: dec("80.1")}},
+		Timestamp: now,
+	}
+
+	t.Run("fresh stored rate", func(t *testing.T) {
+		stored := storedRate(7, now-10)
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetLatestRate", ctx, "usdtrub", models.MethodTop).Return(&stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), mocks.NewMockDepthProvider(t), repo)
+
+		latest, err := svc.GetLatestRate(ctx, "usdtrub", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, latest.Stored)
+		assert.Equal(t, stored.Rate, latest.Rate)
+		assert.InDelta(t, 10*time.Second, latest.Age, float64(time.Second))
+	})
+
+	t.Run("any age without a maximum", func(t *testing.T) {
+		stored := storedRate(7, now-86400)
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetLatestRate", ctx, "usdtrub", models.MethodTop).Return(&stored, nil)
+		svc := service.NewRatesService(zap.NewNop(), mocks.NewMockDepthProvider(t), repo)
+
+		latest, err := svc.GetLatestRate(ctx, "usdtrub", 0)
+		require.NoError(t, err)
+		assert.True(t, latest.Stored)
+	})
+
+	for name, stored := range map[string]func() (*models.StoredRate, error){
+		"stale stored rate": func() (*models.StoredRate, error) {
+			stale := storedRate(7, now-120)
+			return &stale, nil
+		},
+		"no stored rate": func() (*models.StoredRate, error) {
+			return nil, fmt.Errorf("%w: usdtrub", models.ErrRateNotFound)
+		},
+		"stored rates unreadable": func() (*models.StoredRate, error) {
+			return nil, errors.New("db down")
+		},
+	} {
+		t.Run(name+" falls back to a live fetch", func(t *testing.T) {
+			provider := mocks.NewMockDepthProvider(t)
+			provider.On("GetDepth", ctx, "usdtrub").Return(depth, nil)
+			repo := mocks.NewMockRatesRepository(t)
+			repo.On("GetLatestRate", ctx, "usdtrub", models.MethodTop).Return(stored())
+			repo.On("SaveRate", ctx, mock.Anything).Return(nil)
+			svc := service.NewRatesService(zap.NewNop(), provider, repo)
+
+			latest, err := svc.GetLatestRate(ctx, "usdtrub", time.Minute)
+			require.NoError(t, err)
+			assert.False(t, latest.Stored)
+			assert.Equal(t, "80.5", latest.AskPrice.String())
+			assert.Equal(t, models.MethodTop, latest.Method)
+		})
+	}
+
+	t.Run("live fetch failure", func(t *testing.T) {
+		provider := mocks.NewMockDepthProvider(t)
+		provider.On("GetDepth", ctx, "usdtrub").Return(nil, models.ErrUpstreamUnavailable)
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("GetLatestRate", ctx, "usdtrub", models.MethodTop).
+			Return(nil, fmt.Errorf("%w: usdtrub", models.ErrRateNotFound))
+		svc := service.NewRatesService(zap.NewNop(), provider, repo)
+
+		_, err := svc.GetLatestRate(ctx, "usdtrub", time.Minute)
+		require.ErrorIs(t, err, models.ErrUpstreamUnavailable)
+	})
+}
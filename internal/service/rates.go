@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 	"usdt-rate-service/internal/models"
 
 	"go.uber.org/zap"
@@ -12,9 +15,46 @@ type DepthProvider interface {
 	GetDepth(ctx context.Context, market string) (*models.Depth, error)
 }
 
-// RatesRepository is an interface that defines methods to save rates to a repository.
+// TradesProvider is an interface that defines methods to get the recent trades of a specific market.
+type TradesProvider interface {
+	GetTrades(ctx context.Context, market string, limit int) ([]models.Trade, error)
+}
+
+// RatesRepository is an interface that defines methods to save rates to a repository and read them back.
 type RatesRepository interface {
 	SaveRate(ctx context.Context, rate *models.Rate) error
+	GetRateHistory(ctx context.Context, query models.RateHistoryQuery) ([]models.StoredRate, error)
+	GetCandles(ctx context.Context, query models.CandleQuery) ([]models.Candle, error)
+	GetRateAsOf(
+		ctx context.Context,
+		market string,
+		method models.CalculationMethod,
+		timestamp, notBefore int64,
+	) (*models.StoredRate, error)
+	GetLatestRate(ctx context.Context, market string, method models.CalculationMethod) (*models.StoredRate, error)
+}
+
+// TradesRepository is an interface that defines methods to save trades to a repository.
+type TradesRepository interface {
+	SaveTrades(ctx context.Context, trades []models.Trade) error
+}
+
+// MarketsProvider is an interface that defines methods to list the markets of an exchange.
+type MarketsProvider interface {
+	ListMarkets(ctx context.Context) ([]models.Market, error)
+}
+
+// MarketRegistry is a MarketsProvider that can also tell whether a market is supported.
+type MarketRegistry interface {
+	MarketsProvider
+	// ValidateMarket returns an error wrapping models.ErrUnsupportedMarket if the market is not supported.
+	ValidateMarket(ctx context.Context, market string) error
+}
+
+// CircuitStatesProvider is an interface that defines methods to report
+// the circuit breaker states of the depth providers.
+type CircuitStatesProvider interface {
+	CircuitStates() []models.CircuitState
 }
 
 // RatesService provides methods to get and save rates for a specific market.
@@ -22,27 +62,126 @@ type RatesService struct {
 	logger          *zap.Logger
 	depthProvider   DepthProvider
 	ratesRepository RatesRepository
+	markets         MarketRegistry
+	circuits        CircuitStatesProvider
+
+	tradesProvider   TradesProvider
+	tradesRepository TradesRepository
+
+	maxDataAge       time.Duration
+	marketMaxDataAge map[string]time.Duration
+
+	rateAsOfMaxLookback time.Duration
+
+	saved savedRates
+}
+
+// Option configures optional RatesService settings.
+type Option func(*RatesService)
+
+// WithMaxDataAge rejects depth data older than maxAge, or than the market's entry in perMarket if it has one.
+// A zero duration disables the check.
+func WithMaxDataAge(maxAge time.Duration, perMarket map[string]time.Duration) Option {
+	return func(s *RatesService) {
+		s.maxDataAge = maxAge
+		s.marketMaxDataAge = perMarket
+	}
+}
+
+// WithMarketRegistry validates the requested markets against the registry before getting their depth data
+// and lists its markets in ListMarkets.
+func WithMarketRegistry(markets MarketRegistry) Option {
+	return func(s *RatesService) {
+		s.markets = markets
+	}
+}
+
+// WithCircuitStates reports the circuit breaker states of the provider in Health.
+func WithCircuitStates(circuits CircuitStatesProvider) Option {
+	return func(s *RatesService) {
+		s.circuits = circuits
+	}
+}
+
+// WithRateAsOfMaxLookback limits how long before the requested time GetRateAsOf looks for a stored rate.
+// A zero duration looks through the whole history.
+func WithRateAsOfMaxLookback(lookback time.Duration) Option {
+	return func(s *RatesService) {
+		s.rateAsOfMaxLookback = lookback
+	}
+}
+
+// WithTrades serves the recent trades of the provider in GetTrades and stores them in the repository,
+// unless it is nil.
+func WithTrades(provider TradesProvider, repository TradesRepository) Option {
+	return func(s *RatesService) {
+		s.tradesProvider = provider
+		s.tradesRepository = repository
+	}
 }
 
 // NewRatesService creates a new RatesService with the provided logger, depth provider, and rates repository.
-func NewRatesService(logger *zap.Logger, depthProvider DepthProvider, ratesRepository RatesRepository) *RatesService {
-	return &RatesService{
+func NewRatesService(
+	logger *zap.Logger,
+	depthProvider DepthProvider,
+	ratesRepository RatesRepository,
+	opts ...Option,
+) *RatesService {
+	s := &RatesService{
 		logger:          logger,
 		depthProvider:   depthProvider,
 		ratesRepository: ratesRepository,
+		saved:           savedRates{markets: make(map[string]savedDepth)},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetRatesParams holds the parameters of a GetRates call.
+type GetRatesParams struct {
+	// Market is the market to get the rates for, e.g. "usdtrub".
+	Market string
+	// Method is the calculation method. If empty, MethodVWAP is used
+	// when a target volume is set and MethodTop otherwise.
+	Method models.CalculationMethod
+	// N and M are the 1-based book levels used by MethodTopN and MethodAvgNM.
+	N int
+	M int
+	// TargetVolume is the volume to fill on each side of the book with MethodVWAP.
+	TargetVolume models.Decimal
 }
 
 // GetRates retrieves the rates for a specific market by getting depth data from the provider,
 // validating it, creating a Rate model, and saving it to the repository.
 // It returns the Rate model or an error if any step fails.
-func (s *RatesService) GetRates(ctx context.Context, market string) (*models.Rate, error) {
+func (s *RatesService) GetRates(ctx context.Context, params GetRatesParams) (*models.Rate, error) {
+	market := params.Market
 	logger := s.logger.With(
 		zap.String("service", "RatesService"),
 		zap.String("method", "GetRates"),
 		zap.String("market", market),
 	)
 	logger.Debug("Getting rates")
+	if params.Method == "" {
+		params.Method = models.MethodTop
+		if !params.TargetVolume.IsZero() {
+			params.Method = models.MethodVWAP
+		}
+	}
+	calculator, err := NewRateCalculator(params)
+	if err != nil {
+		logger.Error("Invalid calculation parameters", zap.Error(err))
+		return nil, err
+	}
+	if s.markets != nil {
+		if err = s.markets.ValidateMarket(ctx, market); err != nil {
+			logger.Warn("Unsupported market", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	// 1. Get depth data from the provider
 	depth, err := s.depthProvider.GetDepth(ctx, market)
 	if err != nil {
@@ -55,22 +194,152 @@ func (s *RatesService) GetRates(ctx context.Context, market string) (*models.Rat
 		logger.Error("Invalid depth data", zap.Error(err))
 		return nil, err
 	}
+	if err = s.checkDataAge(logger, market, depth); err != nil {
+		logger.Error("Stale depth data", zap.Error(err))
+		return nil, err
+	}
 
 	// 2. Create a Rate model from the depth data
+	quote, err := calculator.Calculate(depth)
+	if err != nil {
+		logger.Error("Failed to calculate rate", zap.Error(err))
+		return nil, err
+	}
 	rate := &models.Rate{
-		Market:    market,
-		AskPrice:  depth.Asks[0].Price,
-		BidPrice:  depth.Bids[0].Price,
-		Timestamp: depth.Timestamp,
+		Market:     market,
+		AskPrice:   quote.AskPrice.Round(),
+		BidPrice:   quote.BidPrice.Round(),
+		Timestamp:  depth.Timestamp,
+		AskPartial: quote.AskPartial,
+		BidPartial: quote.BidPartial,
+		Method:     params.Method,
+		Source:     depth.Source,
+		Venues:     depth.Venues,
+		Cached:     depth.Cached,
+		CacheAge:   depth.CacheAge,
+	}
+	if err = setSpread(rate); err != nil {
+		logger.Error("Crossed book", zap.Error(err))
+		return nil, err
+	}
+
+	// The same rate calculated again from depth data the cache shared adds nothing to the history.
+	// Depth data that did not go through a cache is always fresh and its rates are always saved.
+	shared := !depth.FetchedAt.IsZero()
+	key := savedRate{
+		source:       depth.Source,
+		method:       params.Method,
+		n:            params.N,
+		m:            params.M,
+		targetVolume: params.TargetVolume.String(),
+	}
+	if shared && !s.saved.mark(market, depth.FetchedAt, key) {
+		logger.Debug("Rate already saved", zap.Bool("cached", rate.Cached), zap.Duration("cacheAge", rate.CacheAge))
+		return rate, nil
 	}
 
 	logger.Debug("save rate", zap.Any("rate", rate))
 	// 3. Save the rate to the repository
 	// TODO: Maybe we dont need to return error here, just log it
 	if err = s.ratesRepository.SaveRate(ctx, rate); err != nil {
+		if shared {
+			s.saved.unmark(market, depth.FetchedAt, key)
+		}
 		logger.Error("Failed to save rate", zap.Error(err))
 		return nil, err
 	}
 	logger.Info("Rate saved successfully", zap.Any("rate", rate))
 	return rate, nil
 }
+
+// checkDataAge returns ErrStaleDepth if the depth data is older than the maximum age configured for the market.
+// Timestamps in the future point to clock skew between us and the exchange, they are logged but accepted.
+func (s *RatesService) checkDataAge(logger *zap.Logger, market string, depth *models.Depth) error {
+	maxAge, ok := s.marketMaxDataAge[market]
+	if !ok {
+		maxAge = s.maxDataAge
+	}
+
+	age := time.Since(time.Unix(depth.Timestamp, 0))
+	// The exchange timestamps have a second resolution, so anything less than a second ahead is not a skew.
+	if age <= -time.Second {
+		logger.Warn("Depth timestamp is in the future, the exchange clock may be skewed",
+			zap.Int64("timestamp", depth.Timestamp),
+			zap.Duration("skew", -age),
+		)
+	}
+	if maxAge > 0 && age > maxAge {
+		return fmt.Errorf("%w: data is %s old, maximum is %s", models.ErrStaleDepth, age.Truncate(time.Second), maxAge)
+	}
+	return nil
+}
+
+// ListMarkets returns the markets rates can be requested for.
+// It returns an error wrapping models.ErrNotConfigured if the service has no market registry.
+func (s *RatesService) ListMarkets(ctx context.Context) ([]models.Market, error) {
+	if s.markets == nil {
+		return nil, fmt.Errorf("%w: market list", models.ErrNotConfigured)
+	}
+	markets, err := s.markets.ListMarkets(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list markets", zap.String("service", "RatesService"), zap.Error(err))
+		return nil, err
+	}
+	return markets, nil
+}
+
+// CircuitStates returns the circuit breaker states of the depth providers, or nil if they are not reported.
+func (s *RatesService) CircuitStates() []models.CircuitState {
+	if s.circuits == nil {
+		return nil
+	}
+	return s.circuits.CircuitStates()
+}
+
+// savedRates remembers the rates saved from the latest depth data fetched by the cache for every market,
+// so that a rate is saved once per fetch however many times it is requested. The fetch rather than
+// the timestamp of the depth data identifies it, as distinct depth data may have the same timestamp in seconds.
+type savedRates struct {
+	mu      sync.Mutex
+	markets map[string]savedDepth
+}
+
+// savedDepth is the fetch time of the latest depth data of a market and the rates saved from it.
+type savedDepth struct {
+	fetchedAt time.Time
+	rates     map[savedRate]struct{}
+}
+
+// savedRate identifies a rate calculated from the depth data of a market.
+type savedRate struct {
+	source       string
+	method       models.CalculationMethod
+	n, m         int
+	targetVolume string
+}
+
+// mark records the rate as saved and reports whether it was not recorded yet.
+// Depth data of another fetch replaces the rates recorded for the market.
+func (r *savedRates) mark(market string, fetchedAt time.Time, rate savedRate) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	depth, ok := r.markets[market]
+	if !ok || !depth.fetchedAt.Equal(fetchedAt) {
+		depth = savedDepth{fetchedAt: fetchedAt, rates: make(map[savedRate]struct{})}
+		r.markets[market] = depth
+	}
+	if _, ok = depth.rates[rate]; ok {
+		return false
+	}
+	depth.rates[rate] = struct{}{}
+	return true
+}
+
+// unmark forgets a rate recorded by mark whose saving failed.
+func (r *savedRates) unmark(market string, fetchedAt time.Time, rate savedRate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if depth, ok := r.markets[market]; ok && depth.fetchedAt.Equal(fetchedAt) {
+		delete(depth.rates, rate)
+	}
+}
@@ -3,7 +3,6 @@ package service_test
 import (
 	"context"
 	"errors"
-	"strconv"
 	"testing"
 	"time"
 	"usdt-rate-service/internal/models"
@@ -14,6 +13,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func setupTestService(
@@ -45,17 +45,19 @@ func matchSavedRate(depth *models.Depth) func(*models.Rate) bool {
 		if rate == nil {
 			return false
 		}
-		ask, _ := strconv.ParseFloat(depth.Asks[0].Price, 64)
-		bid, _ := strconv.ParseFloat(depth.Bids[0].Price, 64)
-		return ask > 0 && bid > 0
+		return depth.Asks[0].Price.IsPositive() && depth.Bids[0].Price.IsPositive()
 	}
 }
 
 func assertValidRate(t *testing.T, rate *models.Rate, ask, bid string) {
 	t.Helper()
 	assert.NotNil(t, rate)
-	assert.Equal(t, ask, rate.AskPrice)
-	assert.Equal(t, bid, rate.BidPrice)
+	assert.Equal(t, ask, rate.AskPrice.String())
+	assert.Equal(t, bid, rate.BidPrice.String())
+}
+
+func dec(s string) models.Decimal {
+	return models.MustParseDecimal(s)
 }
 
 func TestRatesService_GetRates(t *testing.T) {
@@ -63,8 +65,8 @@ func TestRatesService_GetRates(t *testing.T) {
 
 	var (
 		validDepth = &models.Depth{
-			Asks:      []models.Order{{Price: "50000.0", Amount: "1.0"}},
-			Bids:      []models.Order{{Price: "49900.0", Amount: "1.5"}},
+			Asks:      []models.Order{{Price: dec("50000.0"), Volume: dec("1.0"), Amount: dec("50000.0")}},
+			Bids:      []models.Order{{Price: dec("49900.0"), Volume: dec("1.5"), Amount: dec("74850.0")}},
 			Timestamp: time.Now().Unix(),
 		}
 
@@ -76,16 +78,20 @@ func TestRatesService_GetRates(t *testing.T) {
 
 	t.Run("successful rate retrieval", func(t *testing.T) {
 		svc, provider, repo := setupTestService(t, validDepth, nil, nil, true)
-		rate, err := svc.GetRates(ctx, "usdtrub")
+		rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
 		require.NoError(t, err)
-		assertValidRate(t, rate, "50000.0", "49900.0")
+		assertValidRate(t, rate, "50000", "49900")
+		assert.Equal(t, models.MethodTop, rate.Method)
+		assert.Equal(t, "49950", rate.MidPrice.String())
+		assert.Equal(t, "100", rate.Spread.String())
+		assert.Equal(t, "20.02002002", rate.SpreadBps.String())
 		provider.AssertExpectations(t)
 		repo.AssertExpectations(t)
 	})
 
 	t.Run("depth provider error", func(t *testing.T) {
 		svc, provider, repo := setupTestService(t, nil, errors.New("provider error"), nil, false)
-		rate, err := svc.GetRates(ctx, "usdtrub")
+		rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
 		require.Error(t, err)
 		assert.Nil(t, rate)
 		provider.AssertExpectations(t)
@@ -94,18 +100,261 @@ func TestRatesService_GetRates(t *testing.T) {
 
 	t.Run("invalid depth data", func(t *testing.T) {
 		svc, provider, repo := setupTestService(t, invalidDepth, nil, nil, false)
-		rate, err := svc.GetRates(ctx, "usdtrub")
+		rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
 		require.Error(t, err)
 		assert.Nil(t, rate)
 		provider.AssertExpectations(t)
 		repo.AssertNotCalled(t, "SaveRate")
 	})
 
+	t.Run("depth level out of range", func(t *testing.T) {
+		svc, provider, repo := setupTestService(t, validDepth, nil, nil, false)
+		params := service.GetRatesParams{Market: "usdtrub", Method: models.MethodTopN, N: 2}
+		rate, err := svc.GetRates(ctx, params)
+		require.ErrorIs(t, err, models.ErrDepthLevelOutOfRange)
+		assert.Nil(t, rate)
+		provider.AssertExpectations(t)
+		repo.AssertNotCalled(t, "SaveRate")
+	})
+
+	for name, bid := range map[string]string{"locked book": "50000.0", "crossed book": "50100.0"} {
+		t.Run(name, func(t *testing.T) {
+			depth := &models.Depth{
+				Asks:      []models.Order{{Price: dec("50000.0"), Volume: dec("1"), Amount: dec("50000")}},
+				Bids:      []models.Order{{Price: dec(bid), Volume: dec("1"), Amount: dec(bid)}},
+				Timestamp: time.Now().Unix(),
+			}
+			svc, provider, repo := setupTestService(t, depth, nil, nil, false)
+			rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
+			require.ErrorIs(t, err, models.ErrCrossedBook)
+			assert.Nil(t, rate)
+			provider.AssertExpectations(t)
+			repo.AssertNotCalled(t, "SaveRate")
+		})
+	}
+
+	t.Run("rate of cached depth is saved once", func(t *testing.T) {
+		cached := *validDepth
+		cached.Cached = true
+		cached.CacheAge = 300 * time.Millisecond
+		cached.FetchedAt = time.Now()
+		svc, provider, repo := setupTestService(t, &cached, nil, nil, false)
+		repo.On("SaveRate", ctx, mock.MatchedBy(func(rate *models.Rate) bool {
+			return rate.Method == models.MethodTop
+		})).Return(nil).Once()
+		repo.On("SaveRate", ctx, mock.MatchedBy(func(rate *models.Rate) bool {
+			return rate.Method == models.MethodVWAP
+		})).Return(nil).Once()
+
+		for range 2 {
+			rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
+			require.NoError(t, err)
+			assertValidRate(t, rate, "50000", "49900")
+			assert.True(t, rate.Cached)
+			assert.Equal(t, 300*time.Millisecond, rate.CacheAge)
+		}
+		// Another method calculates another rate from the same depth data.
+		_, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub", TargetVolume: dec("0.5")})
+		require.NoError(t, err)
+		provider.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rates of distinct fetches with the same timestamp are saved", func(t *testing.T) {
+		first, second := *validDepth, *validDepth
+		first.FetchedAt = time.Now()
+		second.FetchedAt = first.FetchedAt.Add(100 * time.Millisecond)
+		provider := mocks.NewMockDepthProvider(t)
+		provider.On("GetDepth", ctx, "usdtrub").Return(&first, nil).Once()
+		provider.On("GetDepth", ctx, "usdtrub").Return(&second, nil).Once()
+		provider.On("GetDepth", ctx, "usdtrub").Return(validDepth, nil).Twice()
+		repo := mocks.NewMockRatesRepository(t)
+		repo.On("SaveRate", ctx, mock.Anything).Return(nil).Times(4)
+		svc := service.NewRatesService(zap.NewNop(), provider, repo)
+
+		// The last two depth data did not go through a cache, so they are fresh however alike.
+		for range 4 {
+			_, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
+			require.NoError(t, err)
+		}
+	})
+
 	t.Run("repository save error", func(t *testing.T) {
 		svc, provider, repo := setupTestService(t, validDepth, nil, errors.New("save error"), true)
-		_, err := svc.GetRates(ctx, "usdtrub")
-		require.Error(t, err)
+		for range 2 {
+			_, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub"})
+			require.Error(t, err)
+		}
 		provider.AssertExpectations(t)
-		repo.AssertExpectations(t)
+		repo.AssertNumberOfCalls(t, "SaveRate", 2)
+	})
+}
+
+func TestRatesService_GetRates_TargetVolume(t *testing.T) {
+	ctx := context.Background()
+
+	depth := &models.Depth{
+		Asks: []models.Order{
+			{Price: dec("100"), Volume: dec("300"), Amount: dec("30000")},
+			{Price: dec("101"), Volume: dec("700"), Amount: dec("70700")},
+			{Price: dec("103"), Volume: dec("1000"), Amount: dec("103000")},
+		},
+		Bids: []models.Order{
+			{Price: dec("99"), Volume: dec("500"), Amount: dec("49500")},
+			{Price: dec("98"), Volume: dec("500"), Amount: dec("49000")},
+		},
+		Timestamp: time.Now().Unix(),
+	}
+
+	tests := []struct {
+		name        string
+		volume      string
+		wantAsk     string
+		wantBid     string
+		wantAskPart bool
+		wantBidPart bool
+	}{
+		{
+			name:    "top of book",
+			volume:  "0",
+			wantAsk: "100",
+			wantBid: "99",
+		},
+		{
+			name:    "filled by the first level",
+			volume:  "200",
+			wantAsk: "100",
+			wantBid: "99",
+		},
+		{
+			name:    "walks several levels",
+			volume:  "1000",
+			wantAsk: "100.7",
+			wantBid: "98.5",
+		},
+		{
+			name:        "book cannot fill the volume",
+			volume:      "1500",
+			wantAsk:     "101.46666667",
+			wantBid:     "98.5",
+			wantAskPart: false,
+			wantBidPart: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, provider, repo := setupTestService(t, depth, nil, nil, true)
+			rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub", TargetVolume: dec(tt.volume)})
+			require.NoError(t, err)
+			assertValidRate(t, rate, tt.wantAsk, tt.wantBid)
+			assert.Equal(t, tt.wantAskPart, rate.AskPartial)
+			assert.Equal(t, tt.wantBidPart, rate.BidPartial)
+			provider.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("negative volume", func(t *testing.T) {
+		svc, provider, repo := setupTestService(t, depth, nil, nil, false)
+		_, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrub", TargetVolume: dec("-1")})
+		require.ErrorIs(t, err, models.ErrInvalidCalculationParams)
+		provider.AssertNotCalled(t, "GetDepth")
+		repo.AssertNotCalled(t, "SaveRate")
+	})
+}
+
+func TestRatesService_GetRates_DataAge(t *testing.T) {
+	ctx := context.Background()
+
+	depthAt := func(ts time.Time) *models.Depth {
+		return &models.Depth{
+			Asks:      []models.Order{{Price: dec("100"), Volume: dec("1"), Amount: dec("100")}},
+			Bids:      []models.Order{{Price: dec("99"), Volume: dec("1"), Amount: dec("99")}},
+			Timestamp: ts.Unix(),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		market    string
+		depth     *models.Depth
+		wantStale bool
+		wantSkew  bool
+	}{
+		{name: "fresh", market: "usdtrub", depth: depthAt(time.Now().Add(-10 * time.Second))},
+		{name: "stale", market: "usdtrub", depth: depthAt(time.Now().Add(-2 * time.Minute)), wantStale: true},
+		{
+			name:      "market override",
+			market:    "btcusdt",
+			depth:     depthAt(time.Now().Add(-10 * time.Second)),
+			wantStale: true,
+		},
+		{name: "future timestamp", market: "usdtrub", depth: depthAt(time.Now().Add(time.Hour)), wantSkew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mocks.MockDepthProvider{}
+			repo := &mocks.MockRatesRepository{}
+			provider.On("GetDepth", ctx, tt.market).Return(tt.depth, nil)
+			repo.On("SaveRate", ctx, mock.Anything).Return(nil).Maybe()
+
+			core, logs := observer.New(zap.WarnLevel)
+			svc := service.NewRatesService(zap.New(core), provider, repo,
+				service.WithMaxDataAge(time.Minute, map[string]time.Duration{"btcusdt": 5 * time.Second}),
+			)
+			rate, err := svc.GetRates(ctx, service.GetRatesParams{Market: tt.market})
+
+			skewed := logs.FilterMessageSnippet("exchange clock may be skewed").All()
+			if tt.wantSkew {
+				require.Len(t, skewed, 1)
+				skew, ok := skewed[0].ContextMap()["skew"].(time.Duration)
+				require.True(t, ok)
+				assert.InDelta(t, time.Hour, skew, float64(time.Minute))
+			} else {
+				assert.Empty(t, skewed)
+			}
+			if tt.wantStale {
+				require.ErrorIs(t, err, models.ErrStaleDepth)
+				assert.Nil(t, rate)
+				repo.AssertNotCalled(t, "SaveRate")
+				return
+			}
+			require.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRatesService_Markets(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects unsupported markets before getting depth", func(t *testing.T) {
+		provider := mocks.NewMockDepthProvider(t)
+		repo := mocks.NewMockRatesRepository(t)
+		markets := mocks.NewMockMarketRegistry(t)
+		markets.On("ValidateMarket", ctx, "usdtrbu").Return(models.ErrUnsupportedMarket)
+
+		svc := service.NewRatesService(zap.NewNop(), provider, repo, service.WithMarketRegistry(markets))
+		_, err := svc.GetRates(ctx, service.GetRatesParams{Market: "usdtrbu"})
+		require.ErrorIs(t, err, models.ErrUnsupportedMarket)
+		provider.AssertNotCalled(t, "GetDepth")
+	})
+
+	t.Run("lists the markets of the registry", func(t *testing.T) {
+		markets := mocks.NewMockMarketRegistry(t)
+		markets.On("ListMarkets", ctx).Return([]models.Market{{ID: "usdtrub"}}, nil)
+
+		svc := service.NewRatesService(zap.NewNop(), nil, nil, service.WithMarketRegistry(markets))
+		listed, err := svc.ListMarkets(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Market{{ID: "usdtrub"}}, listed)
+	})
+
+	t.Run("no registry", func(t *testing.T) {
+		svc := service.NewRatesService(zap.NewNop(), nil, nil)
+		_, err := svc.ListMarkets(ctx)
+		require.ErrorIs(t, err, models.ErrNotConfigured)
 	})
 }
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"usdt-rate-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTradesLimit is the number of trades returned by GetTrades when no limit is given.
+	DefaultTradesLimit = 50
+	// MaxTradesLimit is the maximum number of trades returned by GetTrades.
+	MaxTradesLimit = 1000
+)
+
+// GetTrades retrieves the most recent trades of a market from the trades provider, newest first,
+// and the price of the last one. A limit of zero or less means DefaultTradesLimit,
+// larger limits are capped at MaxTradesLimit.
+//
+// The trades are stored in the trades repository if one is configured. A failure to store them
+// is logged but does not fail the call, the trades are only kept for reporting.
+func (s *RatesService) GetTrades(ctx context.Context, market string, limit int) (*models.RecentTrades, error) {
+	logger := s.logger.With(
+		zap.String("service", "RatesService"),
+		zap.String("method", "GetTrades"),
+		zap.String("market", market),
+	)
+	if s.tradesProvider == nil {
+		return nil, fmt.Errorf("%w: trades", models.ErrNotConfigured)
+	}
+	if limit <= 0 {
+		limit = DefaultTradesLimit
+	}
+	limit = min(limit, MaxTradesLimit)
+
+	if s.markets != nil {
+		if err := s.markets.ValidateMarket(ctx, market); err != nil {
+			logger.Warn("Unsupported market", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	trades, err := s.tradesProvider.GetTrades(ctx, market, limit)
+	if err != nil {
+		logger.Error("Failed to get trades", zap.Error(err))
+		return nil, err
+	}
+	for _, trade := range trades {
+		if err = trade.Validate(); err != nil {
+			logger.Error("Invalid trade data", zap.Error(err))
+			return nil, err
+		}
+	}
+	if len(trades) == 0 {
+		return nil, fmt.Errorf("%w: market %s", models.ErrNoTrades, market)
+	}
+
+	slices.SortStableFunc(trades, func(a, b models.Trade) int {
+		return cmp.Or(cmp.Compare(b.Timestamp, a.Timestamp), cmp.Compare(b.ID, a.ID))
+	})
+	if len(trades) > limit {
+		trades = trades[:limit]
+	}
+
+	if s.tradesRepository != nil {
+		if err = s.tradesRepository.SaveTrades(ctx, trades); err != nil {
+			logger.Error("Failed to save trades", zap.Error(err))
+		}
+	}
+
+	return &models.RecentTrades{
+		Market:        market,
+		Trades:        trades,
+		LastPrice:     trades[0].Price,
+		LastTimestamp: trades[0].Timestamp,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"usdt-rate-service/internal/models"
+	"usdt-rate-service/internal/service"
+	"usdt-rate-service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func trade(id, timestamp int64, price string) models.Trade {
+	return models.Trade{
+		ID:        id,
+		Market:    "usdtrub",
+		Price:     dec(price),
+		Volume:    dec("1"),
+		Amount:    dec(price),
+		Side:      "buy",
+		Timestamp: timestamp,
+		Source:    "grinex",
+	}
+}
+
+func TestRatesService_GetTrades(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("newest first with the last price", func(t *testing.T) {
+		provider := mocks.NewMockTradesProvider(t)
+		provider.On("GetTrades", ctx, "usdtrub", service.DefaultTradesLimit).Return([]models.Trade{
+			trade(1, 100, "80.1"), trade(3, 200, "80.3"), trade(2, 200, "80.2"),
+		}, nil)
+
+		svc := service.NewRatesService(zap.NewNop(), nil, nil, service.WithTrades(provider, nil))
+		recent, err := svc.GetTrades(ctx, "usdtrub", 0)
+		require.NoError(t, err)
+		ids := make([]int64, len(recent.Trades))
+		for i, tr := range recent.Trades {
+			ids[i] = tr.ID
+		}
+		assert.Equal(t, []int64{3, 2, 1}, ids)
+		assert.Equal(t, "80.3", recent.LastPrice.String())
+		assert.Equal(t, int64(200), recent.LastTimestamp)
+	})
+
+	t.Run("stores the trades and ignores store failures", func(t *testing.T) {
+		provider := mocks.NewMockTradesProvider(t)
+		provider.On("GetTrades", ctx, "usdtrub", service.MaxTradesLimit).
+			Return([]models.Trade{trade(1, 100, "80.1")}, nil)
+		repo := mocks.NewMockTradesRepository(t)
+		repo.On("SaveTrades", ctx, mock.Anything).Return(errors.New("db down"))
+
+		svc := service.NewRatesService(zap.NewNop(), nil, nil, service.WithTrades(provider, repo))
+		recent, err := svc.GetTrades(ctx, "usdtrub", service.MaxTradesLimit+1)
+		require.NoError(t, err)
+		assert.Len(t, recent.Trades, 1)
+	})
+
+	t.Run("invalid trade", func(t *testing.T) {
+		provider := mocks.NewMockTradesProvider(t)
+		provider.On("GetTrades", ctx, "usdtrub", 10).Return([]models.Trade{trade(1, 100, "0")}, nil)
+
+		svc := service.NewRatesService(zap.NewNop(), nil, nil, service.WithTrades(provider, nil))
+		_, err := svc.GetTrades(ctx, "usdtrub", 10)
+		require.ErrorIs(t, err, models.ErrInvalidTrade)
+	})
+
+	t.Run("no trades", func(t *testing.T) {
+		provider := mocks.NewMockTradesProvider(t)
+		provider.On("GetTrades", ctx, "usdtrub", 10).Return(nil, nil)
+
+		svc := service.NewRatesService(zap.NewNop(), nil, nil, service.WithTrades(provider, nil))
+		_, err := svc.GetTrades(ctx, "usdtrub", 10)
+		require.ErrorIs(t, err, models.ErrNoTrades)
+	})
+
+	t.Run("no provider", func(t *testing.T) {
+		svc := service.NewRatesService(zap.NewNop(), nil, nil)
+		_, err := svc.GetTrades(ctx, "usdtrub", 10)
+		require.ErrorIs(t, err, models.ErrNotConfigured)
+	})
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "usdt-rate-service/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCircuitStatesProvider is an autogenerated mock type for the CircuitStatesProvider type
+type MockCircuitStatesProvider struct {
+	mock.Mock
+}
+
+type MockCircuitStatesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCircuitStatesProvider) EXPECT() *MockCircuitStatesProvider_Expecter {
+	return &MockCircuitStatesProvider_Expecter{mock: &_m.Mock}
+}
+
+// CircuitStates provides a mock function with no fields
+func (_m *MockCircuitStatesProvider) CircuitStates() []models.CircuitState {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CircuitStates")
+	}
+
+	var r0 []models.CircuitState
+	if rf, ok := ret.Get(0).(func() []models.CircuitState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CircuitState)
+		}
+	}
+
+	return r0
+}
+
+// MockCircuitStatesProvider_CircuitStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CircuitStates'
+type MockCircuitStatesProvider_CircuitStates_Call struct {
+	*mock.Call
+}
+
+// CircuitStates is a helper method to define mock.On call
+func (_e *MockCircuitStatesProvider_Expecter) CircuitStates() *MockCircuitStatesProvider_CircuitStates_Call {
+	return &MockCircuitStatesProvider_CircuitStates_Call{Call: _e.mock.On("CircuitStates")}
+}
+
+func (_c *MockCircuitStatesProvider_CircuitStates_Call) Run(run func()) *MockCircuitStatesProvider_CircuitStates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCircuitStatesProvider_CircuitStates_Call) Return(_a0 []models.CircuitState) *MockCircuitStatesProvider_CircuitStates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCircuitStatesProvider_CircuitStates_Call) RunAndReturn(run func() []models.CircuitState) *MockCircuitStatesProvider_CircuitStates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCircuitStatesProvider creates a new instance of MockCircuitStatesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCircuitStatesProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCircuitStatesProvider {
+	mock := &MockCircuitStatesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
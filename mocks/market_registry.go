@@ -0,0 +1,142 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "usdt-rate-service/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockMarketRegistry is an autogenerated mock type for the MarketRegistry type
+type MockMarketRegistry struct {
+	mock.Mock
+}
+
+type MockMarketRegistry_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMarketRegistry) EXPECT() *MockMarketRegistry_Expecter {
+	return &MockMarketRegistry_Expecter{mock: &_m.Mock}
+}
+
+// ListMarkets provides a mock function with given fields: ctx
+func (_m *MockMarketRegistry) ListMarkets(ctx context.Context) ([]models.Market, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMarkets")
+	}
+
+	var r0 []models.Market
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Market, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Market); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Market)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMarketRegistry_ListMarkets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListMarkets'
+type MockMarketRegistry_ListMarkets_Call struct {
+	*mock.Call
+}
+
+// ListMarkets is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockMarketRegistry_Expecter) ListMarkets(ctx interface{}) *MockMarketRegistry_ListMarkets_Call {
+	return &MockMarketRegistry_ListMarkets_Call{Call: _e.mock.On("ListMarkets", ctx)}
+}
+
+func (_c *MockMarketRegistry_ListMarkets_Call) Run(run func(ctx context.Context)) *MockMarketRegistry_ListMarkets_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMarketRegistry_ListMarkets_Call) Return(_a0 []models.Market, _a1 error) *MockMarketRegistry_ListMarkets_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMarketRegistry_ListMarkets_Call) RunAndReturn(run func(context.Context) ([]models.Market, error)) *MockMarketRegistry_ListMarkets_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateMarket provides a mock function with given fields: ctx, market
+func (_m *MockMarketRegistry) ValidateMarket(ctx context.Context, market string) error {
+	ret := _m.Called(ctx, market)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateMarket")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, market)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMarketRegistry_ValidateMarket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateMarket'
+type MockMarketRegistry_ValidateMarket_Call struct {
+	*mock.Call
+}
+
+// ValidateMarket is a helper method to define mock.On call
+//   - ctx context.Context
+//   - market string
+func (_e *MockMarketRegistry_Expecter) ValidateMarket(ctx interface{}, market interface{}) *MockMarketRegistry_ValidateMarket_Call {
+	return &MockMarketRegistry_ValidateMarket_Call{Call: _e.mock.On("ValidateMarket", ctx, market)}
+}
+
+func (_c *MockMarketRegistry_ValidateMarket_Call) Run(run func(ctx context.Context, market string)) *MockMarketRegistry_ValidateMarket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMarketRegistry_ValidateMarket_Call) Return(_a0 error) *MockMarketRegistry_ValidateMarket_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMarketRegistry_ValidateMarket_Call) RunAndReturn(run func(context.Context, string) error) *MockMarketRegistry_ValidateMarket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMarketRegistry creates a new instance of MockMarketRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMarketRegistry(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMarketRegistry {
+	mock := &MockMarketRegistry{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,246 @@ func (_m *MockRatesRepository) EXPECT() *MockRatesRepository_Expecter {
 	return &MockRatesRepository_Expecter{mock: &_m.Mock}
 }
 
+// GetCandles provides a mock function with given fields: ctx, query
+func (_m *MockRatesRepository) GetCandles(ctx context.Context, query models.CandleQuery) ([]models.Candle, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCandles")
+	}
+
+	var r0 []models.Candle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.CandleQuery) ([]models.Candle, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.CandleQuery) []models.Candle); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Candle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.CandleQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatesRepository_GetCandles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCandles'
+type MockRatesRepository_GetCandles_Call struct {
+	*mock.Call
+}
+
+// GetCandles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query models.CandleQuery
+func (_e *MockRatesRepository_Expecter) GetCandles(ctx interface{}, query interface{}) *MockRatesRepository_GetCandles_Call {
+	return &MockRatesRepository_GetCandles_Call{Call: _e.mock.On("GetCandles", ctx, query)}
+}
+
+func (_c *MockRatesRepository_GetCandles_Call) Run(run func(ctx context.Context, query models.CandleQuery)) *MockRatesRepository_GetCandles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.CandleQuery))
+	})
+	return _c
+}
+
+func (_c *MockRatesRepository_GetCandles_Call) Return(_a0 []models.Candle, _a1 error) *MockRatesRepository_GetCandles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatesRepository_GetCandles_Call) RunAndReturn(run func(context.Context, models.CandleQuery) ([]models.Candle, error)) *MockRatesRepository_GetCandles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLatestRate provides a mock function with given fields: ctx, market, method
+func (_m *MockRatesRepository) GetLatestRate(ctx context.Context, market string, method models.CalculationMethod) (*models.StoredRate, error) {
+	ret := _m.Called(ctx, market, method)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestRate")
+	}
+
+	var r0 *models.StoredRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.CalculationMethod) (*models.StoredRate, error)); ok {
+		return rf(ctx, market, method)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.CalculationMethod) *models.StoredRate); ok {
+		r0 = rf(ctx, market, method)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoredRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.CalculationMethod) error); ok {
+		r1 = rf(ctx, market, method)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatesRepository_GetLatestRate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestRate'
+type MockRatesRepository_GetLatestRate_Call struct {
+	*mock.Call
+}
+
+// GetLatestRate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - market string
+//   - method models.CalculationMethod
+func (_e *MockRatesRepository_Expecter) GetLatestRate(ctx interface{}, market interface{}, method interface{}) *MockRatesRepository_GetLatestRate_Call {
+	return &MockRatesRepository_GetLatestRate_Call{Call: _e.mock.On("GetLatestRate", ctx, market, method)}
+}
+
+func (_c *MockRatesRepository_GetLatestRate_Call) Run(run func(ctx context.Context, market string, method models.CalculationMethod)) *MockRatesRepository_GetLatestRate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.CalculationMethod))
+	})
+	return _c
+}
+
+func (_c *MockRatesRepository_GetLatestRate_Call) Return(_a0 *models.StoredRate, _a1 error) *MockRatesRepository_GetLatestRate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatesRepository_GetLatestRate_Call) RunAndReturn(run func(context.Context, string, models.CalculationMethod) (*models.StoredRate, error)) *MockRatesRepository_GetLatestRate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRateAsOf provides a mock function with given fields: ctx, market, method, timestamp, notBefore
+func (_m *MockRatesRepository) GetRateAsOf(ctx context.Context, market string, method models.CalculationMethod, timestamp int64, notBefore int64) (*models.StoredRate, error) {
+	ret := _m.Called(ctx, market, method, timestamp, notBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRateAsOf")
+	}
+
+	var r0 *models.StoredRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.CalculationMethod, int64, int64) (*models.StoredRate, error)); ok {
+		return rf(ctx, market, method, timestamp, notBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.CalculationMethod, int64, int64) *models.StoredRate); ok {
+		r0 = rf(ctx, market, method, timestamp, notBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StoredRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.CalculationMethod, int64, int64) error); ok {
+		r1 = rf(ctx, market, method, timestamp, notBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatesRepository_GetRateAsOf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRateAsOf'
+type MockRatesRepository_GetRateAsOf_Call struct {
+	*mock.Call
+}
+
+// GetRateAsOf is a helper method to define mock.On call
+//   - ctx context.Context
+//   - market string
+//   - method models.CalculationMethod
+//   - timestamp int64
+//   - notBefore int64
+func (_e *MockRatesRepository_Expecter) GetRateAsOf(ctx interface{}, market interface{}, method interface{}, timestamp interface{}, notBefore interface{}) *MockRatesRepository_GetRateAsOf_Call {
+	return &MockRatesRepository_GetRateAsOf_Call{Call: _e.mock.On("GetRateAsOf", ctx, market, method, timestamp, notBefore)}
+}
+
+func (_c *MockRatesRepository_GetRateAsOf_Call) Run(run func(ctx context.Context, market string, method models.CalculationMethod, timestamp int64, notBefore int64)) *MockRatesRepository_GetRateAsOf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.CalculationMethod), args[3].(int64), args[4].(int64))
+	})
+	return _c
+}
+
+func (_c *MockRatesRepository_GetRateAsOf_Call) Return(_a0 *models.StoredRate, _a1 error) *MockRatesRepository_GetRateAsOf_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatesRepository_GetRateAsOf_Call) RunAndReturn(run func(context.Context, string, models.CalculationMethod, int64, int64) (*models.StoredRate, error)) *MockRatesRepository_GetRateAsOf_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRateHistory provides a mock function with given fields: ctx, query
+func (_m *MockRatesRepository) GetRateHistory(ctx context.Context, query models.RateHistoryQuery) ([]models.StoredRate, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRateHistory")
+	}
+
+	var r0 []models.StoredRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.RateHistoryQuery) ([]models.StoredRate, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.RateHistoryQuery) []models.StoredRate); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.StoredRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.RateHistoryQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatesRepository_GetRateHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRateHistory'
+type MockRatesRepository_GetRateHistory_Call struct {
+	*mock.Call
+}
+
+// GetRateHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query models.RateHistoryQuery
+func (_e *MockRatesRepository_Expecter) GetRateHistory(ctx interface{}, query interface{}) *MockRatesRepository_GetRateHistory_Call {
+	return &MockRatesRepository_GetRateHistory_Call{Call: _e.mock.On("GetRateHistory", ctx, query)}
+}
+
+func (_c *MockRatesRepository_GetRateHistory_Call) Run(run func(ctx context.Context, query models.RateHistoryQuery)) *MockRatesRepository_GetRateHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RateHistoryQuery))
+	})
+	return _c
+}
+
+func (_c *MockRatesRepository_GetRateHistory_Call) Return(_a0 []models.StoredRate, _a1 error) *MockRatesRepository_GetRateHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatesRepository_GetRateHistory_Call) RunAndReturn(run func(context.Context, models.RateHistoryQuery) ([]models.StoredRate, error)) *MockRatesRepository_GetRateHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveRate provides a mock function with given fields: ctx, rate
 func (_m *MockRatesRepository) SaveRate(ctx context.Context, rate *models.Rate) error {
 	ret := _m.Called(ctx, rate)
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "usdt-rate-service/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTradesProvider is an autogenerated mock type for the TradesProvider type
+type MockTradesProvider struct {
+	mock.Mock
+}
+
+type MockTradesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTradesProvider) EXPECT() *MockTradesProvider_Expecter {
+	return &MockTradesProvider_Expecter{mock: &_m.Mock}
+}
+
+// GetTrades provides a mock function with given fields: ctx, market, limit
+func (_m *MockTradesProvider) GetTrades(ctx context.Context, market string, limit int) ([]models.Trade, error) {
+	ret := _m.Called(ctx, market, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrades")
+	}
+
+	var r0 []models.Trade
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.Trade, error)); ok {
+		return rf(ctx, market, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.Trade); ok {
+		r0 = rf(ctx, market, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Trade)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, market, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTradesProvider_GetTrades_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTrades'
+type MockTradesProvider_GetTrades_Call struct {
+	*mock.Call
+}
+
+// GetTrades is a helper method to define mock.On call
+//   - ctx context.Context
+//   - market string
+//   - limit int
+func (_e *MockTradesProvider_Expecter) GetTrades(ctx interface{}, market interface{}, limit interface{}) *MockTradesProvider_GetTrades_Call {
+	return &MockTradesProvider_GetTrades_Call{Call: _e.mock.On("GetTrades", ctx, market, limit)}
+}
+
+func (_c *MockTradesProvider_GetTrades_Call) Run(run func(ctx context.Context, market string, limit int)) *MockTradesProvider_GetTrades_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockTradesProvider_GetTrades_Call) Return(_a0 []models.Trade, _a1 error) *MockTradesProvider_GetTrades_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTradesProvider_GetTrades_Call) RunAndReturn(run func(context.Context, string, int) ([]models.Trade, error)) *MockTradesProvider_GetTrades_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTradesProvider creates a new instance of MockTradesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTradesProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTradesProvider {
+	mock := &MockTradesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "usdt-rate-service/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTradesRepository is an autogenerated mock type for the TradesRepository type
+type MockTradesRepository struct {
+	mock.Mock
+}
+
+type MockTradesRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTradesRepository) EXPECT() *MockTradesRepository_Expecter {
+	return &MockTradesRepository_Expecter{mock: &_m.Mock}
+}
+
+// SaveTrades provides a mock function with given fields: ctx, trades
+func (_m *MockTradesRepository) SaveTrades(ctx context.Context, trades []models.Trade) error {
+	ret := _m.Called(ctx, trades)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTrades")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Trade) error); ok {
+		r0 = rf(ctx, trades)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTradesRepository_SaveTrades_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTrades'
+type MockTradesRepository_SaveTrades_Call struct {
+	*mock.Call
+}
+
+// SaveTrades is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trades []models.Trade
+func (_e *MockTradesRepository_Expecter) SaveTrades(ctx interface{}, trades interface{}) *MockTradesRepository_SaveTrades_Call {
+	return &MockTradesRepository_SaveTrades_Call{Call: _e.mock.On("SaveTrades", ctx, trades)}
+}
+
+func (_c *MockTradesRepository_SaveTrades_Call) Run(run func(ctx context.Context, trades []models.Trade)) *MockTradesRepository_SaveTrades_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Trade))
+	})
+	return _c
+}
+
+func (_c *MockTradesRepository_SaveTrades_Call) Return(_a0 error) *MockTradesRepository_SaveTrades_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTradesRepository_SaveTrades_Call) RunAndReturn(run func(context.Context, []models.Trade) error) *MockTradesRepository_SaveTrades_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTradesRepository creates a new instance of MockTradesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTradesRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTradesRepository {
+	mock := &MockTradesRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}